@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/devjefster/GoValidator/validator"
+)
+
+// kind classifies field types by how the built-in rules treat them.
+// The rules match concrete types, so e.g. min only applies to int.
+type kind int
+
+const (
+	kindUnsupported kind = iota
+	kindString
+	kindInt      // int, the only type min and max apply to
+	kindNumber   // int32, int64, float32 and float64, which the sign rules accept
+	kindOtherInt // other integer types, which the sign rules reject
+	kindBool
+	kindSlice
+	kindArray
+	kindMap
+	kindPointer       // a pointer to a non-struct type
+	kindStruct        // a struct declared in the package
+	kindStructPointer // a pointer to a struct declared in the package
+	kindOpaque        // a type without fields to validate, such as time.Time
+)
+
+// classify returns the kind of a field type
+func (g *generator) classify(typ ast.Expr) kind {
+	switch typ := typ.(type) {
+	case *ast.Ident:
+		switch typ.Name {
+		case "string":
+			return kindString
+		case "int":
+			return kindInt
+		case "int32", "rune", "int64", "float32", "float64":
+			return kindNumber
+		case "int8", "int16", "uint", "uint8", "byte", "uint16", "uint32", "uint64", "uintptr":
+			return kindOtherInt
+		case "bool":
+			return kindBool
+		}
+		if g.isStruct(typ.Name) {
+			return kindStruct
+		}
+	case *ast.ArrayType:
+		if typ.Len == nil {
+			return kindSlice
+		}
+		return kindArray
+	case *ast.MapType:
+		return kindMap
+	case *ast.StarExpr:
+		switch g.classify(typ.X) {
+		case kindStruct:
+			return kindStructPointer
+		case kindOpaque, kindUnsupported:
+			return kindUnsupported
+		default:
+			return kindPointer
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := typ.X.(*ast.Ident); ok && pkg.Name == "time" && (typ.Sel.Name == "Time" || typ.Sel.Name == "Duration") {
+			return kindOpaque
+		}
+	}
+	return kindUnsupported
+}
+
+// writer writes the statements validating a value
+type writer struct {
+	g     *generator
+	deps  map[string]bool
+	depth int // nesting of dives, naming loop variables
+	buf   bytes.Buffer
+}
+
+// plan writes the validation of the value v of type typ against the rules of a tag,
+// mirroring the compiled plans of the reflective engine: omitempty/omitnil, the rules,
+// then either a dive or the fields of a nested struct
+func (w *writer) plan(v string, typ ast.Expr, nodes []validator.TagRule) error {
+	k := w.g.classify(typ)
+	if k == kindUnsupported {
+		if len(nodes) == 0 && !mayHoldStruct(typ) {
+			return nil
+		}
+		return fmt.Errorf("type %s is not supported", types.ExprString(typ))
+	}
+
+	// omitempty and omitnil apply to the whole tag wherever they are, and rules
+	// following them see the value optional pointers point to
+	skip, omitted, descend := "", false, v
+	for _, node := range nodes {
+		if node.Name == "dive" {
+			break
+		}
+		if node.Name != "omitempty" && node.Name != "omitnil" {
+			continue
+		}
+		if node.Params != nil || omitted {
+			return fmt.Errorf("invalid %s", node.Name)
+		}
+		omitted = true
+		var err error
+		if skip, err = w.omitCondition(node.Name, v, typ, k); err != nil {
+			return err
+		}
+		if k == kindPointer || k == kindStructPointer {
+			v, typ = "*"+v, typ.(*ast.StarExpr).X
+			k = w.g.classify(typ)
+		}
+	}
+
+	var body bytes.Buffer
+	var dive []validator.TagRule
+	for i, node := range nodes {
+		if node.Name == "dive" {
+			if node.Params != nil {
+				return fmt.Errorf("invalid dive")
+			}
+			dive = nodes[i+1:]
+			break
+		}
+		if node.Name == "omitempty" || node.Name == "omitnil" {
+			continue
+		}
+		if err := w.rule(&body, v, k, node); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case dive != nil:
+		if err := w.dive(&body, v, typ, k, dive); err != nil {
+			return err
+		}
+	case k == kindStruct:
+		w.deps[typ.(*ast.Ident).Name] = true
+		fmt.Fprintf(&body, "%s.validateFields(p, errs)\n", descend)
+	case k == kindStructPointer:
+		w.deps[typ.(*ast.StarExpr).X.(*ast.Ident).Name] = true
+		fmt.Fprintf(&body, "if %[1]s != nil {\n%[1]s.validateFields(p, errs)\n}\n", v)
+	}
+
+	if skip != "" && body.Len() > 0 {
+		fmt.Fprintf(&w.buf, "if %s {\n%s}\n", negate(skip), body.String())
+	} else {
+		w.buf.Write(body.Bytes())
+	}
+	return nil
+}
+
+// negate returns the negation of a condition written by omitCondition
+func negate(cond string) string {
+	switch {
+	case strings.Contains(cond, "||"):
+		return "!(" + cond + ")"
+	case strings.HasPrefix(cond, "!"):
+		return cond[1:]
+	}
+	before, after, _ := strings.Cut(cond, " == ")
+	return before + " != " + after
+}
+
+// mayHoldStruct reports whether the reflective engine may descend into a field of an
+// unsupported type, whose rules the generator cannot see
+func mayHoldStruct(typ ast.Expr) bool {
+	switch typ := typ.(type) {
+	case *ast.InterfaceType, *ast.FuncType, *ast.ChanType, *ast.MapType, *ast.ArrayType:
+		return false
+	case *ast.StarExpr:
+		return mayHoldStruct(typ.X)
+	}
+	return true
+}
+
+// omitCondition returns the condition under which omitempty or omitnil skips v,
+// or "" when it never does
+func (w *writer) omitCondition(name, v string, typ ast.Expr, k kind) (string, error) {
+	if name == "omitnil" {
+		switch k {
+		case kindSlice, kindMap, kindPointer, kindStructPointer:
+			return v + " == nil", nil
+		}
+		return "", nil
+	}
+	if k == kindPointer {
+		// A pointer is empty when it is nil or points to an empty value
+		elem, _ := emptyCondition("*"+v, w.g.classify(typ.(*ast.StarExpr).X))
+		return v + " == nil || " + elem, nil
+	}
+	cond, ok := emptyCondition(v, k)
+	if !ok {
+		return "", fmt.Errorf("omitempty on %s is not supported", types.ExprString(typ))
+	}
+	return cond, nil
+}
+
+// emptyCondition returns the condition under which the required rule rejects v
+func emptyCondition(v string, k kind) (string, bool) {
+	switch k {
+	case kindString:
+		return "strings.TrimSpace(" + v + ") == \"\"", true
+	case kindInt, kindNumber, kindOtherInt:
+		return v + " == 0", true
+	case kindBool:
+		return "!" + v, true
+	case kindSlice, kindArray, kindMap:
+		return "len(" + v + ") == 0", true
+	case kindPointer, kindStructPointer:
+		return v + " == nil", true
+	}
+	return "", false
+}
+
+// rule writes a built-in rule. Rules that never fail for the kind of v write nothing,
+// rules that always fail for it report unconditionally.
+func (w *writer) rule(buf *bytes.Buffer, v string, k kind, node validator.TagRule) error {
+	var cond string
+	always := false
+	switch node.Name {
+	case "required", "non-empty":
+		c, ok := emptyCondition(v, k)
+		if !ok {
+			return fmt.Errorf("%s on this type is not supported", node.Name)
+		}
+		cond = c
+	case "non-null":
+		if k == kindPointer || k == kindStructPointer {
+			cond = v + " == nil"
+		}
+	case "non-blank":
+		if k == kindString {
+			cond = "strings.TrimSpace(" + v + ") == \"\""
+		}
+	case "min", "max":
+		n, err := intParam(node)
+		if err != nil {
+			return err
+		}
+		if k == kindInt {
+			op := "<"
+			if node.Name == "max" {
+				op = ">"
+			}
+			cond = fmt.Sprintf("%s %s %d", v, op, n)
+		}
+	case "email":
+		if k == kindString {
+			cond = "!validator.IsEmail(" + v + ")"
+		}
+	case "e164":
+		if k == kindString {
+			cond = "!validator.IsE164(" + v + ")"
+		}
+	case "isTrue":
+		if k == kindBool {
+			cond = "!" + v
+		} else {
+			always = true
+		}
+	case "positive", "negative", "positiveOrZero", "negativeOrZero":
+		if k == kindInt || k == kindNumber {
+			op := map[string]string{"positive": ">", "negative": "<", "positiveOrZero": ">=", "negativeOrZero": "<="}[node.Name]
+			cond = fmt.Sprintf("!(%s %s 0)", v, op)
+		} else {
+			always = true
+		}
+	case "size", "minSize", "maxSize":
+		n, err := intParam(node)
+		if err != nil {
+			return err
+		}
+		if k == kindString || k == kindSlice || k == kindArray || k == kindMap {
+			op := map[string]string{"size": "!=", "minSize": "<", "maxSize": ">"}[node.Name]
+			cond = fmt.Sprintf("len(%s) %s %d", v, op, n)
+		} else {
+			always = true
+		}
+	default:
+		return fmt.Errorf("rule %s is not supported", node.Name)
+	}
+	if node.Name != "min" && node.Name != "max" && node.Name != "size" && node.Name != "minSize" && node.Name != "maxSize" && node.Params != nil {
+		return fmt.Errorf("%s takes no parameters", node.Name)
+	}
+
+	if strings.Contains(cond, "strings.") {
+		w.g.imports["strings"] = true
+	}
+	fail := fmt.Sprintf("errs.Fail(p, %q, %s%s)\n", node.Name, v, paramArgs(node.Params))
+	switch {
+	case always:
+		buf.WriteString(fail)
+	case cond != "":
+		fmt.Fprintf(buf, "if %s {\n%s}\n", cond, fail)
+	}
+	return nil
+}
+
+// intParam parses the single integer parameter of a rule
+func intParam(node validator.TagRule) (int, error) {
+	if len(node.Params) != 1 {
+		return 0, fmt.Errorf("%s expects 1 parameter", node.Name)
+	}
+	n, err := strconv.Atoi(node.Params[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s parameter %q", node.Name, node.Params[0])
+	}
+	return n, nil
+}
+
+// paramArgs renders the parameters of a rule as trailing arguments of Fail
+func paramArgs(params []string) string {
+	var sb strings.Builder
+	for _, param := range params {
+		sb.WriteString(", " + strconv.Quote(param))
+	}
+	return sb.String()
+}
+
+// dive writes a loop validating every element of v against the rules after a dive
+func (w *writer) dive(buf *bytes.Buffer, v string, typ ast.Expr, k kind, nodes []validator.TagRule) error {
+	elem := &writer{g: w.g, deps: w.deps, depth: w.depth + 1}
+	i, e := fmt.Sprintf("i%d", w.depth), fmt.Sprintf("e%d", w.depth)
+	switch k {
+	case kindSlice, kindArray:
+		if err := elem.element(e, typ.(*ast.ArrayType).Elt, nodes); err != nil {
+			return err
+		}
+		if elem.buf.Len() > 0 {
+			fmt.Fprintf(buf, "for %s, %s := range %s {\np := p.Index(%s)\n%s}\n", i, e, v, i, elem.buf.String())
+		}
+	case kindMap:
+		m := typ.(*ast.MapType)
+		if key, ok := m.Key.(*ast.Ident); !ok || key.Name != "string" {
+			return fmt.Errorf("dive into maps with keys of type %s is not supported", types.ExprString(m.Key))
+		}
+		if err := elem.element(e, m.Value, nodes); err != nil {
+			return err
+		}
+		if elem.buf.Len() > 0 {
+			key := fmt.Sprintf("k%d", w.depth)
+			w.g.imports["maps"], w.g.imports["slices"] = true, true
+			fmt.Fprintf(buf, "for _, %[1]s := range slices.Sorted(maps.Keys(%[2]s)) {\n%[3]s := %[2]s[%[1]s]\np := p.Key(%[1]s)\n%[4]s}\n",
+				key, v, e, elem.buf.String())
+		}
+	default:
+		return fmt.Errorf("dive into %s is not supported", types.ExprString(typ))
+	}
+	return nil
+}
+
+// element writes the validation of an element of a collection
+func (w *writer) element(e string, typ ast.Expr, nodes []validator.TagRule) error {
+	for _, node := range nodes {
+		if node.Name == "keys" || node.Name == "endkeys" {
+			return fmt.Errorf("%s is not supported", node.Name)
+		}
+	}
+	return w.plan(e, typ, nodes)
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/devjefster/GoValidator/validator"
+)
+
+// generatedHeader marks the files written by the generator, which are not read back
+const generatedHeader = "// Code generated by govalidator-gen. DO NOT EDIT."
+
+// validatorImport is the import path of the validator package
+const validatorImport = "github.com/devjefster/GoValidator/validator"
+
+// result is a generated file with the warnings about types left to the reflective engine
+type result struct {
+	file     string
+	src      []byte
+	warnings []string
+}
+
+// typeInfo is a struct type of the package and the code generated for it
+type typeInfo struct {
+	name    string
+	spec    *ast.StructType
+	methods map[string]bool
+	root    bool // gets a Validate method, not only validateFields
+
+	body string          // statements of validateFields
+	deps map[string]bool // struct types validateFields calls into
+	err  error           // why the type is left to the reflective engine
+}
+
+// generator collects the structs of a package and generates their validation
+type generator struct {
+	pkg     string
+	types   map[string]*typeInfo
+	order   []string // struct types in declaration order
+	imports map[string]bool
+}
+
+// generate parses the package in dir and returns the generated file
+func generate(dir string, roots []string, output string) (*result, error) {
+	g := &generator{types: make(map[string]*typeInfo), imports: map[string]bool{validatorImport: true}}
+	if err := g.load(dir, output); err != nil {
+		return nil, err
+	}
+	if output == "" {
+		output = g.pkg + "_validate.go"
+	}
+
+	if roots == nil {
+		for _, name := range g.order {
+			if hasTags(g.types[name].spec) {
+				roots = append(roots, name)
+			}
+		}
+	}
+	for _, name := range roots {
+		info, ok := g.types[name]
+		if !ok {
+			return nil, fmt.Errorf("type %s is not a struct declared in package %s", name, g.pkg)
+		}
+		info.root = true
+	}
+
+	var warnings []string
+	for _, name := range g.order {
+		if info := g.types[name]; info.root && info.methods["Validate"] {
+			info.root = false
+			info.err = errors.New("it already has a Validate method")
+			warnings = append(warnings, fmt.Sprintf("%s: skipped: %v", name, info.err))
+		}
+	}
+
+	// Generate the roots and every struct they validate, then leave to the reflective
+	// engine the types that depend on a type left to it
+	pending := slices.Clone(roots)
+	for len(pending) > 0 {
+		info := g.types[pending[0]]
+		pending = pending[1:]
+		if info.body != "" || info.deps != nil || info.err != nil {
+			continue
+		}
+		g.generateType(info)
+		for dep := range info.deps {
+			pending = append(pending, dep)
+		}
+	}
+	// Generated code does not track the structs being validated, so it could not stop
+	// at pointer cycles like the reflective engine does
+	for _, name := range g.order {
+		if info := g.types[name]; info.err == nil && g.reaches(info, name, make(map[string]bool)) {
+			info.err = errors.New("it is recursive")
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, name := range g.order {
+			info := g.types[name]
+			if info.err != nil {
+				continue
+			}
+			for _, dep := range sortedKeys(info.deps) {
+				if g.types[dep].err != nil {
+					info.err = fmt.Errorf("it nests %s, which is validated by the reflective engine", dep)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, name := range g.order {
+		info := g.types[name]
+		switch {
+		case info.root && info.err != nil:
+			warnings = append(warnings, fmt.Sprintf("%s: validated by the reflective engine: %v", name, info.err))
+			g.writeFallback(&buf, info)
+		case info.err == nil && info.deps != nil:
+			g.writeType(&buf, info)
+		}
+	}
+	src, err := g.file(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &result{file: output, src: src, warnings: warnings}, nil
+}
+
+// reaches reports whether the validation of info calls into the type named target
+func (g *generator) reaches(info *typeInfo, target string, seen map[string]bool) bool {
+	for dep := range info.deps {
+		if dep == target {
+			return true
+		}
+		if !seen[dep] {
+			seen[dep] = true
+			if g.reaches(g.types[dep], target, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// load parses the non-test files of the package in dir, except generated validation files
+func (g *generator) load(dir, output string) error {
+	fset := token.NewFileSet()
+	filter := func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != output
+	}
+	pkgs, err := parser.ParseDir(fset, dir, filter, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	if len(pkgs) != 1 {
+		return fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+	for name, pkg := range pkgs {
+		g.pkg = name
+		for _, path := range sortedKeys(pkg.Files) {
+			file := pkg.Files[path]
+			if isGeneratedByUs(file) {
+				continue
+			}
+			g.collect(file)
+		}
+	}
+	return nil
+}
+
+// isGeneratedByUs reports whether file was written by the generator
+func isGeneratedByUs(file *ast.File) bool {
+	return len(file.Comments) > 0 && file.Comments[0].Pos() < file.Package &&
+		strings.HasPrefix(file.Comments[0].List[0].Text, generatedHeader)
+}
+
+// collect records the struct types and the methods declared in file
+func (g *generator) collect(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.TypeParams != nil {
+					continue
+				}
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					g.info(ts.Name.Name).spec = st
+					g.order = append(g.order, ts.Name.Name)
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) == 0 {
+				continue
+			}
+			recv := decl.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				g.info(ident.Name).methods[decl.Name.Name] = true
+			}
+		}
+	}
+}
+
+// info returns the entry of a type, creating it on first use
+func (g *generator) info(name string) *typeInfo {
+	info, ok := g.types[name]
+	if !ok {
+		info = &typeInfo{name: name, methods: make(map[string]bool)}
+		g.types[name] = info
+	}
+	return info
+}
+
+// isStruct reports whether name is a struct type declared in the package
+func (g *generator) isStruct(name string) bool {
+	info, ok := g.types[name]
+	return ok && info.spec != nil
+}
+
+// hasTags reports whether a struct has a field with a validate tag
+func hasTags(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if tagOf(field).Get("validate") != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// tagOf returns the struct tag of a field
+func tagOf(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag)
+}
+
+// generateType generates validateFields for a struct, or records why it cannot be generated
+func (g *generator) generateType(info *typeInfo) {
+	info.deps = make(map[string]bool)
+	switch {
+	case info.methods["Validate"] && !info.root:
+		info.err = errors.New("it has a Validate method")
+		return
+	case info.methods["ValidateWith"]:
+		info.err = errors.New("it has a ValidateWith method")
+		return
+	case info.methods["validateFields"]:
+		info.err = errors.New("it already has a validateFields method")
+		return
+	}
+
+	w := &writer{g: g, deps: info.deps}
+	recv := receiverName(info.name)
+	for _, field := range info.spec.Fields.List {
+		if len(field.Names) == 0 {
+			info.err = errors.New("embedded fields are not supported")
+			return
+		}
+		tag := tagOf(field)
+		if _, ok := tag.Lookup("msg"); ok {
+			info.err = errors.New("msg tags are not supported")
+			return
+		}
+		rules := tag.Get("validate")
+		if rules == "-" || (rules == "" && !mayHoldStruct(field.Type)) {
+			continue
+		}
+		var nodes []validator.TagRule
+		if rules != "" {
+			var err error
+			if nodes, err = validator.ParseTag(rules); err != nil {
+				info.err = err
+				return
+			}
+		}
+		for _, name := range field.Names {
+			if !ast.IsExported(name.Name) {
+				continue
+			}
+			json, _, _ := strings.Cut(tag.Get("json"), ",")
+			if json == "" || json == "-" {
+				json = name.Name
+			}
+			body := &writer{g: g, deps: info.deps}
+			if err := body.plan(recv+"."+name.Name, field.Type, nodes); err != nil {
+				info.err = fmt.Errorf("field %s: %v", name.Name, err)
+				return
+			}
+			if body.buf.Len() > 0 {
+				fmt.Fprintf(&w.buf, "{\np := p.Field(%q, %q)\n%s}\n", name.Name, json, body.buf.String())
+			}
+		}
+	}
+	info.body = w.buf.String()
+}
+
+// receiverName returns the receiver of the generated methods of a type
+func receiverName(typeName string) string {
+	switch name := strings.ToLower(typeName[:1]); name {
+	case "p", "e", "i", "k":
+		// Taken by the path, elements, indexes and keys of the generated code
+		return "s"
+	default:
+		return name
+	}
+}
+
+// writeType writes the generated methods of a type
+func (g *generator) writeType(buf *bytes.Buffer, info *typeInfo) {
+	recv := receiverName(info.name)
+	if info.root {
+		fmt.Fprintf(buf, "// Validate validates %[2]s like validator.Validate, without reflection\n"+
+			"func (%[1]s *%[2]s) Validate() validator.ValidationErrors {\n"+
+			"if %[1]s == nil {\nreturn validator.Validate(%[1]s)\n}\n"+
+			"var errs validator.ValidationErrors\n"+
+			"%[1]s.validateFields(validator.RootPath(%[2]q), &errs)\n"+
+			"return errs\n}\n\n", recv, info.name)
+	}
+	fmt.Fprintf(buf, "// validateFields validates the fields of %[2]s at p\n"+
+		"func (%[1]s *%[2]s) validateFields(p validator.Path, errs *validator.ValidationErrors) {\n%[3]s}\n\n",
+		recv, info.name, info.body)
+}
+
+// writeFallback writes a Validate method delegating to the reflective engine
+func (g *generator) writeFallback(buf *bytes.Buffer, info *typeInfo) {
+	recv := receiverName(info.name)
+	fmt.Fprintf(buf, "// Validate validates %[2]s with validator.Validate: %[3]v\n"+
+		"func (%[1]s *%[2]s) Validate() validator.ValidationErrors {\n"+
+		"return validator.Validate(%[1]s)\n}\n\n", recv, info.name, info.err)
+}
+
+// file assembles and formats the generated file
+func (g *generator) file(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\n\npackage %s\n\nimport (\n", generatedHeader, g.pkg)
+	for _, path := range sortedKeys(g.imports) {
+		if path != validatorImport {
+			fmt.Fprintf(&buf, "%q\n", path)
+		}
+	}
+	fmt.Fprintf(&buf, "\n%q\n)\n\n", validatorImport)
+	buf.Write(body)
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Test that the committed code of the example package is what the generator writes
+func TestGenerateExample(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	result, err := generate(dir, nil, "")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join(dir, result.file))
+	if err != nil {
+		t.Fatalf("reading the committed code: %v", err)
+	}
+	if !bytes.Equal(result.src, want) {
+		t.Errorf("%s is out of date, run go generate ./...", result.file)
+	}
+
+	warnings := []string{
+		"Event: validated by the reflective engine: field Date: rule date is not supported",
+		"Node: validated by the reflective engine: it is recursive",
+	}
+	if !slices.Equal(result.warnings, warnings) {
+		t.Errorf("expected warnings %q, got %q", warnings, result.warnings)
+	}
+}
+
+// Test that types using unsupported features are left to the reflective engine
+func TestGenerateFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		warning string
+	}{
+		{"custom rule", "type T struct {\n\tA string `validate:\"slug\"`\n}", "rule slug is not supported"},
+		{"alternatives", "type T struct {\n\tA string `validate:\"email|e164\"`\n}", "rule | is not supported"},
+		{"groups", "type T struct {\n\tA string `validate:\"groups=admin,required\"`\n}", "rule groups is not supported"},
+		{"message", "type T struct {\n\tA string `validate:\"required\" msg:\"required=Enter A\"`\n}", "msg tags are not supported"},
+		{"malformed tag", "type T struct {\n\tA int `validate:\"min=x\"`\n}", `invalid min parameter "x"`},
+		{"embedded", "type T struct {\n\tU\n\tA string `validate:\"required\"`\n}\ntype U struct{}", "embedded fields are not supported"},
+		{"interface", "type T struct {\n\tA any `validate:\"required\"`\n}", "type any is not supported"},
+		{"struct method", "type T struct {\n\tA string `validate:\"required\"`\n}\nfunc (T) ValidateWith(r *validator.Report) {}", "it has a ValidateWith method"},
+		{"nested fallback", "type T struct {\n\tA U `validate:\"required\"`\n}\ntype U struct{}", "required on this type is not supported"},
+		{"nests fallback", "type T struct {\n\tU U\n}\ntype U struct {\n\tA string `validate:\"slug\"`\n}", "it nests U"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := "package p\n\nimport \"github.com/devjefster/GoValidator/validator\"\n\nvar _ validator.Rule\n\n" + tt.src + "\n"
+			if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			result, err := generate(dir, []string{"T"}, "")
+			if err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+			if len(result.warnings) != 1 || !strings.HasPrefix(result.warnings[0], "T: validated by the reflective engine: ") ||
+				!strings.Contains(result.warnings[0], tt.warning) {
+				t.Errorf("expected a warning about %q, got %q", tt.warning, result.warnings)
+			}
+			if !bytes.Contains(result.src, []byte("return validator.Validate(t)\n}")) || bytes.Contains(result.src, []byte("validateFields")) {
+				t.Errorf("expected a Validate method calling validator.Validate, got\n%s", result.src)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Package example holds the models the tests of govalidator-gen generate code for.
+package example
+
+import "time"
+
+//go:generate go run github.com/devjefster/GoValidator/cmd/govalidator-gen
+
+// User exercises the rules the generator supports
+type User struct {
+	Name     string            `json:"name" validate:"required,non-blank,maxSize=20"`
+	Email    string            `json:"email,omitempty" validate:"required,email"`
+	Phone    *string           `json:"phone" validate:"omitempty,e164"`
+	Age      int               `validate:"min=18,max=130"`
+	Score    float64           `validate:"positiveOrZero"`
+	Level    int8              `validate:"positive"`
+	Accepted bool              `validate:"isTrue"`
+	Tags     []string          `json:"tags" validate:"maxSize=3,dive,required,minSize=2"`
+	Labels   map[string]string `validate:"omitnil,dive,non-blank"`
+	Codes    [2]int            `validate:"dive,negativeOrZero"`
+	Nickname *string           `validate:"non-null"`
+	Address  Address           `json:"address"`
+	Billing  *Address          `json:"billing" validate:"omitnil"`
+	Friends  []*Friend         `validate:"omitempty,dive"`
+	Joined   time.Time
+	internal string
+}
+
+// Address is validated through the users holding it
+type Address struct {
+	Street string `json:"street" validate:"required"`
+	Zip    string `validate:"size=5"`
+}
+
+// Friend is validated through the users listing it
+type Friend struct {
+	Name  string            `validate:"required"`
+	Pets  map[string]Pet    `validate:"dive"`
+	Notes map[string]string `validate:"maxSize=1"`
+}
+
+// Pet is nested in maps
+type Pet struct {
+	Name string `validate:"required"`
+}
+
+// Event uses a date rule, so it is validated by the reflective engine
+type Event struct {
+	Name string `validate:"required"`
+	Date string `validate:"date=2006-01-02"`
+}
+
+// Node is recursive, so it is validated by the reflective engine
+type Node struct {
+	Value int   `validate:"positive"`
+	Next  *Node `validate:"omitnil"`
+}
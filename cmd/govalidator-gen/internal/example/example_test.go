@@ -0,0 +1,31 @@
+package example
+
+import (
+	"testing"
+
+	"github.com/devjefster/GoValidator/validator/validatortest"
+)
+
+// Test that the generated Validate methods report the errors of the reflective engine
+func TestParity(t *testing.T) {
+	phone, badPhone, blank, nick := "+5511987654321", "123", " ", "jo"
+	valid := User{
+		Name: "Jane", Email: "jane@example.com", Phone: &phone, Age: 30, Score: 1.5, Accepted: true,
+		Tags: []string{"go", "dev"}, Labels: map[string]string{"team": "core"}, Codes: [2]int{-1, 0},
+		Nickname: &nick, Address: Address{Street: "Main", Zip: "12345"},
+	}
+	invalid := User{
+		Name: "  ", Email: "jane", Phone: &badPhone, Age: 17, Score: -1, Level: 3,
+		Tags: []string{"go", "", "x", "long"}, Labels: map[string]string{"b": " ", "a": "", "c": "ok"}, Codes: [2]int{2, -3},
+		Address: Address{Zip: "1"}, Billing: &Address{Street: "Side"},
+		Friends: []*Friend{nil, {Pets: map[string]Pet{"rex": {}, "ace": {Name: "Ace"}}, Notes: map[string]string{"a": "", "b": ""}}},
+	}
+	overflow := valid
+	overflow.Name, overflow.Age, overflow.Phone, overflow.Labels = "a name longer than twenty", 200, &blank, map[string]string{}
+
+	validatortest.AssertParity(t,
+		&User{}, &valid, &invalid, &overflow, (*User)(nil),
+		&Address{}, &Friend{Name: "Max"}, &Pet{},
+		&Event{Date: "tomorrow"}, &Node{Value: 1, Next: &Node{}},
+	)
+}
@@ -0,0 +1,232 @@
+// Code generated by govalidator-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/devjefster/GoValidator/validator"
+)
+
+// Validate validates User like validator.Validate, without reflection
+func (u *User) Validate() validator.ValidationErrors {
+	if u == nil {
+		return validator.Validate(u)
+	}
+	var errs validator.ValidationErrors
+	u.validateFields(validator.RootPath("User"), &errs)
+	return errs
+}
+
+// validateFields validates the fields of User at p
+func (u *User) validateFields(p validator.Path, errs *validator.ValidationErrors) {
+	{
+		p := p.Field("Name", "name")
+		if strings.TrimSpace(u.Name) == "" {
+			errs.Fail(p, "required", u.Name)
+		}
+		if strings.TrimSpace(u.Name) == "" {
+			errs.Fail(p, "non-blank", u.Name)
+		}
+		if len(u.Name) > 20 {
+			errs.Fail(p, "maxSize", u.Name, "20")
+		}
+	}
+	{
+		p := p.Field("Email", "email")
+		if strings.TrimSpace(u.Email) == "" {
+			errs.Fail(p, "required", u.Email)
+		}
+		if !validator.IsEmail(u.Email) {
+			errs.Fail(p, "email", u.Email)
+		}
+	}
+	{
+		p := p.Field("Phone", "phone")
+		if !(u.Phone == nil || strings.TrimSpace(*u.Phone) == "") {
+			if !validator.IsE164(*u.Phone) {
+				errs.Fail(p, "e164", *u.Phone)
+			}
+		}
+	}
+	{
+		p := p.Field("Age", "Age")
+		if u.Age < 18 {
+			errs.Fail(p, "min", u.Age, "18")
+		}
+		if u.Age > 130 {
+			errs.Fail(p, "max", u.Age, "130")
+		}
+	}
+	{
+		p := p.Field("Score", "Score")
+		if !(u.Score >= 0) {
+			errs.Fail(p, "positiveOrZero", u.Score)
+		}
+	}
+	{
+		p := p.Field("Level", "Level")
+		errs.Fail(p, "positive", u.Level)
+	}
+	{
+		p := p.Field("Accepted", "Accepted")
+		if !u.Accepted {
+			errs.Fail(p, "isTrue", u.Accepted)
+		}
+	}
+	{
+		p := p.Field("Tags", "tags")
+		if len(u.Tags) > 3 {
+			errs.Fail(p, "maxSize", u.Tags, "3")
+		}
+		for i0, e0 := range u.Tags {
+			p := p.Index(i0)
+			if strings.TrimSpace(e0) == "" {
+				errs.Fail(p, "required", e0)
+			}
+			if len(e0) < 2 {
+				errs.Fail(p, "minSize", e0, "2")
+			}
+		}
+	}
+	{
+		p := p.Field("Labels", "Labels")
+		if u.Labels != nil {
+			for _, k0 := range slices.Sorted(maps.Keys(u.Labels)) {
+				e0 := u.Labels[k0]
+				p := p.Key(k0)
+				if strings.TrimSpace(e0) == "" {
+					errs.Fail(p, "non-blank", e0)
+				}
+			}
+		}
+	}
+	{
+		p := p.Field("Codes", "Codes")
+		for i0, e0 := range u.Codes {
+			p := p.Index(i0)
+			if !(e0 <= 0) {
+				errs.Fail(p, "negativeOrZero", e0)
+			}
+		}
+	}
+	{
+		p := p.Field("Nickname", "Nickname")
+		if u.Nickname == nil {
+			errs.Fail(p, "non-null", u.Nickname)
+		}
+	}
+	{
+		p := p.Field("Address", "address")
+		u.Address.validateFields(p, errs)
+	}
+	{
+		p := p.Field("Billing", "billing")
+		if u.Billing != nil {
+			u.Billing.validateFields(p, errs)
+		}
+	}
+	{
+		p := p.Field("Friends", "Friends")
+		if len(u.Friends) != 0 {
+			for i0, e0 := range u.Friends {
+				p := p.Index(i0)
+				if e0 != nil {
+					e0.validateFields(p, errs)
+				}
+			}
+		}
+	}
+}
+
+// Validate validates Address like validator.Validate, without reflection
+func (a *Address) Validate() validator.ValidationErrors {
+	if a == nil {
+		return validator.Validate(a)
+	}
+	var errs validator.ValidationErrors
+	a.validateFields(validator.RootPath("Address"), &errs)
+	return errs
+}
+
+// validateFields validates the fields of Address at p
+func (a *Address) validateFields(p validator.Path, errs *validator.ValidationErrors) {
+	{
+		p := p.Field("Street", "street")
+		if strings.TrimSpace(a.Street) == "" {
+			errs.Fail(p, "required", a.Street)
+		}
+	}
+	{
+		p := p.Field("Zip", "Zip")
+		if len(a.Zip) != 5 {
+			errs.Fail(p, "size", a.Zip, "5")
+		}
+	}
+}
+
+// Validate validates Friend like validator.Validate, without reflection
+func (f *Friend) Validate() validator.ValidationErrors {
+	if f == nil {
+		return validator.Validate(f)
+	}
+	var errs validator.ValidationErrors
+	f.validateFields(validator.RootPath("Friend"), &errs)
+	return errs
+}
+
+// validateFields validates the fields of Friend at p
+func (f *Friend) validateFields(p validator.Path, errs *validator.ValidationErrors) {
+	{
+		p := p.Field("Name", "Name")
+		if strings.TrimSpace(f.Name) == "" {
+			errs.Fail(p, "required", f.Name)
+		}
+	}
+	{
+		p := p.Field("Pets", "Pets")
+		for _, k0 := range slices.Sorted(maps.Keys(f.Pets)) {
+			e0 := f.Pets[k0]
+			p := p.Key(k0)
+			e0.validateFields(p, errs)
+		}
+	}
+	{
+		p := p.Field("Notes", "Notes")
+		if len(f.Notes) > 1 {
+			errs.Fail(p, "maxSize", f.Notes, "1")
+		}
+	}
+}
+
+// Validate validates Pet like validator.Validate, without reflection
+func (s *Pet) Validate() validator.ValidationErrors {
+	if s == nil {
+		return validator.Validate(s)
+	}
+	var errs validator.ValidationErrors
+	s.validateFields(validator.RootPath("Pet"), &errs)
+	return errs
+}
+
+// validateFields validates the fields of Pet at p
+func (s *Pet) validateFields(p validator.Path, errs *validator.ValidationErrors) {
+	{
+		p := p.Field("Name", "Name")
+		if strings.TrimSpace(s.Name) == "" {
+			errs.Fail(p, "required", s.Name)
+		}
+	}
+}
+
+// Validate validates Event with validator.Validate: field Date: rule date is not supported
+func (s *Event) Validate() validator.ValidationErrors {
+	return validator.Validate(s)
+}
+
+// Validate validates Node with validator.Validate: it is recursive
+func (n *Node) Validate() validator.ValidationErrors {
+	return validator.Validate(n)
+}
@@ -0,0 +1,63 @@
+// Command govalidator-gen generates reflection-free Validate methods from the
+// validate tags of the structs of a package. Add to a file of the package:
+//
+//	//go:generate go run github.com/devjefster/GoValidator/cmd/govalidator-gen
+//
+// For every struct with validate tags, and every struct of the package they nest,
+// it writes to <package>_validate.go a method
+//
+//	func (u *User) Validate() validator.ValidationErrors
+//
+// reporting the same errors as validator.Validate with the default configuration.
+// Types using features the generator does not support, such as custom rules,
+// groups, "when", alternatives or struct-level methods, get a Validate method that
+// calls validator.Validate instead; a warning names each of them. Struct
+// validators registered at run time are not run by generated code.
+//
+// Usage:
+//
+//	govalidator-gen [-type T1,T2] [-output file] [dir]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeList := flag.String("type", "", "comma-separated types to generate for; defaults to every struct with validate tags")
+	output := flag.String("output", "", "name of the generated file; defaults to <package>_validate.go")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: govalidator-gen [-type T1,T2] [-output file] [dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	} else if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	var types []string
+	if *typeList != "" {
+		types = strings.Split(*typeList, ",")
+	}
+
+	result, err := generate(dir, types, *output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "govalidator-gen: %v\n", err)
+		os.Exit(1)
+	}
+	for _, warning := range result.warnings {
+		fmt.Fprintf(os.Stderr, "govalidator-gen: %s\n", warning)
+	}
+	if err := os.WriteFile(filepath.Join(dir, result.file), result.src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "govalidator-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/devjefster/GoValidator/cmd/govalidator-vet
+
+go 1.25.0
+
+require (
+	github.com/devjefster/GoValidator v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.47.0
+)
+
+require (
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/devjefster/GoValidator => ../..
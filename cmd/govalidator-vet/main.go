@@ -0,0 +1,22 @@
+// Command govalidator-vet checks the validate tags of struct fields, reporting unknown
+// rules, malformed parameters, invalid date layouts and rules that do not apply to the
+// type of their field. Run it on its own or through go vet:
+//
+//	go -C cmd/govalidator-vet install .
+//	go vet -vettool=$(which govalidator-vet) ./...
+//
+// It is a module of its own, so that the library does not require the Go version
+// of golang.org/x/tools.
+//
+// Pass -rules=slug,even to accept custom rules registered at run time, and -tag to
+// check a tag other than validate.
+package main
+
+import (
+	"github.com/devjefster/GoValidator/cmd/govalidator-vet/validatetag"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(validatetag.Analyzer)
+}
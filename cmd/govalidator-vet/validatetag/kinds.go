@@ -0,0 +1,115 @@
+package validatetag
+
+import "go/types"
+
+// ruleKind describes the types of the values a predefined rule checks
+type ruleKind struct {
+	accepts func(typ types.Type) bool
+	want    string // the accepted types, for messages
+	fails   bool   // values of other types fail the rule instead of passing it
+}
+
+var (
+	stringKind   = ruleKind{accepts: is(types.String), want: "string"}
+	intKind      = ruleKind{accepts: is(types.Int), want: "int"}
+	boolKind     = ruleKind{accepts: is(types.Bool), want: "bool", fails: true}
+	numberKind   = ruleKind{accepts: is(types.Int, types.Int32, types.Int64, types.Float32, types.Float64), want: "int, int32, int64, float32 and float64", fails: true}
+	sizeKind     = ruleKind{accepts: hasLength, want: "strings, slices, arrays and maps", fails: true}
+	durationKind = ruleKind{accepts: isDuration, want: "time.Duration and *time.Duration"}
+	dateKind     = ruleKind{accepts: isDate, want: "string, time.Time, *time.Time and driver.Valuer", fails: true}
+	pointerKind  = ruleKind{accepts: isPointer, want: "pointers"}
+)
+
+// ruleKinds holds the predefined rules that only check some types. Other rules, such as
+// required or the cross-field rules, accept values of any type.
+var ruleKinds = map[string]ruleKind{
+	"non-null":  pointerKind,
+	"non-blank": stringKind,
+	"email":     stringKind,
+	"e164":      stringKind,
+	"min":       intKind,
+	"max":       intKind,
+	"isTrue":    boolKind,
+
+	"positive":       numberKind,
+	"negative":       numberKind,
+	"positiveOrZero": numberKind,
+	"negativeOrZero": numberKind,
+
+	"size":    sizeKind,
+	"minSize": sizeKind,
+	"maxSize": sizeKind,
+
+	"minDuration": durationKind,
+	"maxDuration": durationKind,
+
+	"date":             dateKind,
+	"date-format":      dateKind,
+	"after":            dateKind,
+	"before":           dateKind,
+	"between":          dateKind,
+	"past":             dateKind,
+	"future":           dateKind,
+	"pastInclusive":    dateKind,
+	"futureInclusive":  dateKind,
+	"past-inclusive":   dateKind,
+	"future-inclusive": dateKind,
+	"afterfield":       dateKind,
+	"beforefield":      dateKind,
+}
+
+// is returns a predicate matching the given basic types. Rules type-assert values,
+// so named types such as type Email string do not match.
+func is(kinds ...types.BasicKind) func(types.Type) bool {
+	return func(typ types.Type) bool {
+		for _, kind := range kinds {
+			if types.Identical(typ, types.Typ[kind]) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// hasLength reports whether len applies to the values of typ
+func hasLength(typ types.Type) bool {
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		return u.Info()&types.IsString != 0
+	case *types.Slice, *types.Array, *types.Map:
+		return true
+	}
+	return false
+}
+
+// isPointer reports whether the values of typ can be nil pointers
+func isPointer(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Pointer)
+	return ok
+}
+
+// isDuration reports whether typ is time.Duration or a pointer to it
+func isDuration(typ types.Type) bool {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	return isNamed(typ, "time", "Duration")
+}
+
+// isDate reports whether the date rules read a date from the values of typ
+func isDate(typ types.Type) bool {
+	if is(types.String)(typ) || isNamed(typ, "time", "Time") {
+		return true
+	}
+	if ptr, ok := typ.(*types.Pointer); ok && isNamed(ptr.Elem(), "time", "Time") {
+		return true
+	}
+	// Wrappers such as sql.NullTime hold their date behind driver.Valuer
+	return types.NewMethodSet(typ).Lookup(nil, "Value") != nil
+}
+
+// isNamed reports whether typ is the named type pkg.name
+func isNamed(typ types.Type, pkg, name string) bool {
+	named, ok := types.Unalias(typ).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == pkg && named.Obj().Name() == name
+}
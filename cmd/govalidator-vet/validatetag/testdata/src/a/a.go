@@ -0,0 +1,41 @@
+package a
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Email string
+
+type Address struct {
+	Zip string
+}
+
+type User struct {
+	Name     string            `validate:"requierd"`                              // want `invalid validate tag: unknown validation rule "requierd"`
+	Nick     string            `validate:"required,minSize=abc"`                  // want `invalid validate tag: minSize: invalid integer parameter "abc"`
+	Born     string            `validate:"required,date=YYYY-MM-DD"`              // want `invalid validate tag: invalid date layout "YYYY-MM-DD": use the reference time, e.g. 2006-01-02`
+	Confirm  string            `validate:"eqfield=Pasword"`                       // want `invalid validate tag: eqfield: unknown field "Pasword" in User`
+	Zip      string            `validate:"eqfield=Address.Zip"`                   //
+	Country  string            `validate:"required_if=Address.City x"`            // want `invalid validate tag: required_if: unknown field "City" in Address`
+	Age      int               `validate:"email"`                                 // want `email is ignored for fields of type int: it applies to string`
+	Work     Email             `validate:"required,email"`                        // want `email is ignored for fields of type Email: it applies to string`
+	Phone    *string           `validate:"e164"`                                  // want `e164 is ignored for fields of type \*string: it applies to string`
+	Mobile   *string           `validate:"omitempty,e164"`                        //
+	Count    int64             `validate:"min=1"`                                 // want `min is ignored for fields of type int64: it applies to int`
+	Score    uint              `validate:"positive"`                              // want `positive always fails for fields of type uint: it applies to int, int32, int64, float32 and float64`
+	Tags     []string          `validate:"maxSize=3,dive,email|e164"`             //
+	Codes    []int             `validate:"dive,email"`                            // want `email is ignored for fields of type int: it applies to string`
+	Labels   map[int]string    `validate:"dive,keys,non-blank,endkeys"`           // want `non-blank is ignored for fields of type int: it applies to string`
+	Level    int               `validate:"dive,required"`                         // want `dive only applies to slices, arrays and maps, not int`
+	Joined   time.Time         `validate:"past"`                                  //
+	Left     sql.NullTime      `validate:"omitempty,after=2020-01-01 2006-01-02"` //
+	Expiry   int               `validate:"future"`                                // want `future always fails for fields of type int: it applies to string, time.Time, \*time.Time and driver.Valuer`
+	Grace    time.Duration     `validate:"minDuration=1h"`                        //
+	Retry    int               `validate:"not(maxDuration=1h)"`                   // want `maxDuration is ignored for fields of type int: it applies to time.Duration and \*time.Duration`
+	Accepted *bool             `validate:"isTrue"`                                // want `isTrue always fails for fields of type \*bool: it applies to bool`
+	Any      interface{}       `validate:"email"`                                 //
+	Extra    map[string]string `validate:"slug"`                                  //
+	Address  Address
+	secret   string `validate:"required"` // want `validate tag of unexported field secret is ignored`
+}
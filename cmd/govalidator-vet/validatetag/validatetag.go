@@ -0,0 +1,279 @@
+// Package validatetag defines an Analyzer that checks the validate tags of struct fields.
+//
+// It reports the tag errors Validate would only report at run time: syntax errors,
+// unknown rules, malformed parameters, invalid date layouts and references to unknown
+// fields. It also reports rules that do not apply to the type of their field, which
+// Validate silently ignores or always fails, such as email on an int.
+//
+// Rules registered at run time are unknown to the analyzer; list them with -rules.
+package validatetag
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/devjefster/GoValidator/validator"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer checks validate tags
+var Analyzer = &analysis.Analyzer{
+	Name:     "validatetag",
+	Doc:      "check the validate tags of struct fields\n\nvalidatetag reports tags Validate would reject at run time and rules that do not apply to the type of their field.",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var (
+	tagName     = "validate"
+	customRules string
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&tagName, "tag", tagName, "struct tag holding the rules")
+	Analyzer.Flags.StringVar(&customRules, "rules", "", "comma-separated custom rules registered at run time")
+}
+
+// checker checks the tags of a package against the predefined and custom rules
+type checker struct {
+	pass   *analysis.Pass
+	v      *validator.Validator
+	custom map[string]bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	c := &checker{pass: pass, v: validator.New(validator.WithTagName(tagName)), custom: make(map[string]bool)}
+	for _, name := range strings.Split(customRules, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if err := c.v.RegisterRule(name, func(string, interface{}, ...string) error { return nil }); err != nil {
+			return nil, err
+		}
+		c.custom[name] = true
+	}
+
+	// Fields referenced by rules are looked up in the named type of declared structs,
+	// which the type specs are visited before
+	named := make(map[*ast.StructType]types.Type)
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.TypeSpec)(nil), (*ast.StructType)(nil)}, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.TypeSpec:
+			if st, ok := n.Type.(*ast.StructType); ok {
+				if obj := pass.TypesInfo.Defs[n.Name]; obj != nil {
+					named[st] = obj.Type()
+				}
+			}
+		case *ast.StructType:
+			typ, ok := named[n]
+			if !ok {
+				typ = pass.TypesInfo.TypeOf(n)
+			}
+			if typ != nil {
+				c.checkStruct(n, typ)
+			}
+		}
+	})
+	return nil, nil
+}
+
+// checkStruct checks the tags of the fields of a struct of type typ
+func (c *checker) checkStruct(st *ast.StructType, typ types.Type) {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		raw, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		tag, ok := reflect.StructTag(raw).Lookup(tagName)
+		if !ok || tag == "" || tag == "-" {
+			continue
+		}
+		pos := tagPos(field.Tag, tag)
+
+		// Validate only reads the tags of exported fields
+		name := fieldName(field)
+		if !ast.IsExported(name) {
+			c.pass.Reportf(field.Tag.Pos(), "%s tag of unexported field %s is ignored", tagName, name)
+			continue
+		}
+
+		if err := c.v.CheckTag(tag, func(path string) error { return resolve(c.pass.Pkg, typ, path) }); err != nil {
+			tagErr := err.(*validator.TagError)
+			c.pass.Reportf(pos(tagErr.Offset), "invalid %s tag: %s", tagName, tagErr.Msg)
+			continue
+		}
+		rules, _ := validator.ParseTag(tag)
+		c.checkTypes(rules, c.pass.TypesInfo.TypeOf(field.Type), pos)
+	}
+}
+
+// fieldName returns the name of a field, the name of its type for embedded fields
+func fieldName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	typ := field.Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch typ := typ.(type) {
+	case *ast.Ident:
+		return typ.Name
+	case *ast.SelectorExpr:
+		return typ.Sel.Name
+	case *ast.IndexExpr:
+		return fieldName(&ast.Field{Type: typ.X})
+	case *ast.IndexListExpr:
+		return fieldName(&ast.Field{Type: typ.X})
+	}
+	return "_"
+}
+
+// tagPos returns the position of a byte offset of the rules within a tag literal. Offsets
+// fall on the start of the literal when the rules are escaped in it.
+func tagPos(lit *ast.BasicLit, tag string) func(offset int) token.Pos {
+	quoted := tagName + ":" + strconv.Quote(tag)
+	start := strings.Index(lit.Value, quoted)
+	if lit.Value[0] != '`' || start < 0 || strconv.Quote(tag) != `"`+tag+`"` {
+		return func(int) token.Pos { return lit.Pos() }
+	}
+	start += len(tagName) + 2
+	return func(offset int) token.Pos { return lit.Pos() + token.Pos(start+offset) }
+}
+
+// resolve checks that path names an exported field of typ, through nested structs
+func resolve(pkg *types.Package, typ types.Type, path string) error {
+	for _, name := range strings.Split(path, ".") {
+		typ = deref(typ)
+		if _, ok := typ.Underlying().(*types.Struct); !ok {
+			return fmt.Errorf("%q is not a struct field path", path)
+		}
+		obj, _, _ := types.LookupFieldOrMethod(typ, false, pkg, name)
+		field, ok := obj.(*types.Var)
+		if !ok || !field.IsField() || !field.Exported() {
+			return fmt.Errorf("unknown field %q in %s", name, types.TypeString(typ, types.RelativeTo(pkg)))
+		}
+		typ = field.Type()
+	}
+	return nil
+}
+
+// deref returns the type pointer types point to
+func deref(typ types.Type) types.Type {
+	for {
+		ptr, ok := typ.Underlying().(*types.Pointer)
+		if !ok {
+			return typ
+		}
+		typ = ptr.Elem()
+	}
+}
+
+// checkTypes checks that the rules of a tag apply to the type of the value they validate,
+// following the compiled plans of Validate: omitempty and omitnil make the rules see the
+// value optional pointers point to, and a dive hands the remaining rules to the elements
+func (c *checker) checkTypes(rules []validator.TagRule, typ types.Type, pos func(int) token.Pos) {
+	for _, rule := range rules {
+		if rule.Name == "dive" {
+			break
+		}
+		if rule.Name == "omitempty" || rule.Name == "omitnil" {
+			if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+				typ = ptr.Elem()
+			}
+			break
+		}
+	}
+
+	for i, rule := range rules {
+		switch rule.Name {
+		case "dive":
+			c.checkDive(rule, rules[i+1:], typ, pos)
+			return
+		case "omitempty", "omitnil", "tz", "groups", "redact", "msg", "when":
+			continue
+		}
+		c.checkRule(rule, typ, pos)
+	}
+}
+
+// checkDive checks the rules of a dive against the keys and elements of typ
+func (c *checker) checkDive(dive validator.TagRule, rules []validator.TagRule, typ types.Type, pos func(int) token.Pos) {
+	typ = deref(typ)
+	if isDynamic(typ) {
+		return
+	}
+	var key, elem types.Type
+	switch u := typ.Underlying().(type) {
+	case *types.Slice:
+		elem = u.Elem()
+	case *types.Array:
+		elem = u.Elem()
+	case *types.Map:
+		key, elem = u.Key(), u.Elem()
+	default:
+		c.pass.Reportf(pos(dive.Offset), "dive only applies to slices, arrays and maps, not %s", c.typeString(typ))
+		return
+	}
+
+	if len(rules) > 0 && rules[0].Name == "keys" {
+		end := len(rules)
+		for i, rule := range rules {
+			if rule.Name == "endkeys" {
+				end = i
+				break
+			}
+		}
+		if key == nil {
+			c.pass.Reportf(pos(rules[0].Offset), "keys only applies to maps, not %s", c.typeString(typ))
+		} else {
+			c.checkTypes(rules[1:end], key, pos)
+		}
+		if end == len(rules) {
+			return
+		}
+		rules = rules[end+1:]
+	}
+	c.checkTypes(rules, elem, pos)
+}
+
+// checkRule checks a rule, or the rules it combines, against typ
+func (c *checker) checkRule(rule validator.TagRule, typ types.Type, pos func(int) token.Pos) {
+	if rule.Name == "|" || rule.Name == "not" {
+		for _, arg := range rule.Args {
+			c.checkRule(arg, typ, pos)
+		}
+		return
+	}
+	kind, ok := ruleKinds[rule.Name]
+	if !ok || c.custom[rule.Name] || isDynamic(typ) || kind.accepts(typ) {
+		return
+	}
+	effect := "is ignored"
+	if kind.fails {
+		effect = "always fails"
+	}
+	c.pass.Reportf(pos(rule.Offset), "%s %s for fields of type %s: it applies to %s", rule.Name, effect, c.typeString(typ), kind.want)
+}
+
+// typeString prints a type relative to the package being checked
+func (c *checker) typeString(typ types.Type) string {
+	return types.TypeString(typ, types.RelativeTo(c.pass.Pkg))
+}
+
+// isDynamic reports whether the type of the values of typ is only known at run time
+func isDynamic(typ types.Type) bool {
+	_, param := typ.(*types.TypeParam)
+	return param || types.IsInterface(typ)
+}
@@ -0,0 +1,16 @@
+package validatetag
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// Test the diagnostics reported for the tags of the testdata package
+func TestAnalyzer(t *testing.T) {
+	if err := Analyzer.Flags.Set("rules", "slug"); err != nil {
+		t.Fatal(err)
+	}
+	defer Analyzer.Flags.Set("rules", "")
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
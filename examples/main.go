@@ -29,7 +29,7 @@ func main() {
 		IsActive:      true,
 		Tags:          []string{"Go", "Golang"},
 		Birthdate:     "1995-06-15",
-		Subscription:  "2025-01-01",
+		Subscription:  "2099-01-01",
 		PhoneNumbers:  []string{"123-456-7890", "987-654-3210"},
 		Comment:       "This is a sample comment.",
 		FavoriteItems: []int{1, 2},
@@ -0,0 +1,82 @@
+package validator
+
+import "testing"
+
+type benchAddress struct {
+	Street  string `validate:"required,minSize=3,maxSize=64"`
+	ZipCode string `validate:"required,size=5"`
+}
+
+type benchUser struct {
+	Username  string   `validate:"required,minSize=3,maxSize=15"`
+	Email     string   `validate:"required,email"`
+	Age       int      `validate:"required,min=18,max=130"`
+	Balance   float64  `validate:"positiveOrZero"`
+	Tags      []string `validate:"minSize=1,maxSize=5,dive,required"`
+	Birthdate string   `validate:"date=2006-01-02"`
+	Address   benchAddress
+	Labels    map[string]string `validate:"dive,keys,minSize=2,endkeys,required"`
+}
+
+func newBenchUser() benchUser {
+	return benchUser{
+		Username:  "johndoe",
+		Email:     "johndoe@example.com",
+		Age:       25,
+		Balance:   100.50,
+		Tags:      []string{"go", "golang"},
+		Birthdate: "1995-06-15",
+		Address:   benchAddress{Street: "Main St", ZipCode: "12345"},
+		Labels:    map[string]string{"env": "prod"},
+	}
+}
+
+func BenchmarkValidateValid(b *testing.B) {
+	v := New()
+	user := newBenchUser()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if errs := v.Validate(&user); errs.HasErrors() {
+			b.Fatal(errs)
+		}
+	}
+}
+
+func BenchmarkValidateInvalid(b *testing.B) {
+	v := New()
+	user := newBenchUser()
+	user.Email = "not-an-email"
+	user.Age = 12
+	user.Address.ZipCode = ""
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if errs := v.Validate(&user); !errs.HasErrors() {
+			b.Fatal("expected errors")
+		}
+	}
+}
+
+func BenchmarkValidateParallel(b *testing.B) {
+	v := New()
+	user := newBenchUser()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			v.Validate(&user)
+		}
+	})
+}
+
+// BenchmarkValidateColdPlan compiles the plan on every call, which is what every
+// call used to cost before plans were cached per type
+func BenchmarkValidateColdPlan(b *testing.B) {
+	user := newBenchUser()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		New().Validate(&user)
+	}
+}
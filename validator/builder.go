@@ -0,0 +1,306 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Builder declares the rules of a struct type in code, for types whose fields cannot
+// carry validate tags, such as generated code. Rules registered for a type replace the
+// tags of the fields they are declared for and are compiled exactly like tags:
+//
+//	b := validator.For[User]()
+//	validator.Field(b, func(u *User) *string { return &u.Email }, validator.Required[string](), validator.Email())
+//	validator.Field(b, func(u *User) *int { return &u.Age }, validator.Min(18))
+//	err := b.Register()
+type Builder[S any] struct {
+	typ        reflect.Type
+	fields     map[int]*fieldSpec
+	validators []StructValidator
+	err        error
+}
+
+// fieldSpec holds the rules declared for a field
+type fieldSpec struct {
+	rules    []string
+	optional bool     // rules after omitnil apply to the value the field points to
+	elems    []string // rules of the elements, after a dive
+}
+
+// RuleSpec is a rule declared with a Builder for fields of type T, equivalent to the
+// same rule in a tag. Rules applying to a single type, such as Email, are only accepted
+// for fields of that type; rules applying to any type take T as a type argument.
+type RuleSpec[T any] struct {
+	tag string
+}
+
+// For starts declaring the rules of the struct type S
+func For[S any]() *Builder[S] {
+	b := &Builder[S]{typ: reflect.TypeOf((*S)(nil)).Elem(), fields: make(map[int]*fieldSpec)}
+	if b.typ.Kind() != reflect.Struct {
+		b.err = fmt.Errorf("rules can only be declared for structs, got %s", b.typ)
+	}
+	return b
+}
+
+// Field declares rules for the field of S whose address get returns,
+// e.g. func(u *User) *string { return &u.Email }. Rules of repeated calls for a field accumulate.
+func Field[S, T any](b *Builder[S], get func(*S) *T, rules ...RuleSpec[T]) {
+	if spec := b.field(get); spec != nil {
+		spec.rules = appendRules(spec.rules, rules)
+	}
+}
+
+// Optional declares rules for the value a pointer field points to. Like the omitnil modifier,
+// it skips every rule of the field when it is nil, e.g.
+// Optional(b, func(u *User) **int { return &u.Age }, Min(18))
+func Optional[S, T any](b *Builder[S], get func(*S) **T, rules ...RuleSpec[T]) {
+	if spec := b.field(get); spec != nil {
+		if !spec.optional {
+			spec.rules = append(spec.rules, "omitnil")
+			spec.optional = true
+		}
+		spec.rules = appendRules(spec.rules, rules)
+	}
+}
+
+// Elements declares rules for every element of a slice field, like the rules after a dive
+func Elements[S, E any](b *Builder[S], get func(*S) *[]E, rules ...RuleSpec[E]) {
+	if spec := b.field(get); spec != nil {
+		spec.elems = appendRules(spec.elems, rules)
+	}
+}
+
+// appendRules appends the tags of rules
+func appendRules[T any](tags []string, rules []RuleSpec[T]) []string {
+	for _, rule := range rules {
+		tags = append(tags, rule.tag)
+	}
+	return tags
+}
+
+// field returns the rules of the field whose address get returns, or nil after an error
+func (b *Builder[S]) field(get interface{}) *fieldSpec {
+	if b.err != nil {
+		return nil
+	}
+	index, err := b.fieldIndex(get)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	if b.fields[index] == nil {
+		b.fields[index] = &fieldSpec{}
+	}
+	return b.fields[index]
+}
+
+// Struct adds a struct-level validation run after the fields of every S, like a
+// StructValidator registered for S
+func (b *Builder[S]) Struct(fn func(r *Report, s S)) *Builder[S] {
+	if fn == nil {
+		if b.err == nil {
+			b.err = fmt.Errorf("struct validator for %s must not be nil", b.typ)
+		}
+		return b
+	}
+	b.validators = append(b.validators, func(r *Report, s interface{}) {
+		fn(r, s.(S))
+	})
+	return b
+}
+
+// Register registers the rules on the default Validator
+func (b *Builder[S]) Register() error {
+	return b.RegisterOn(defaultValidator)
+}
+
+// RegisterOn makes v validate S with the declared rules, replacing the rules and struct
+// validations declared by a previous Builder for S. It returns the first error of the declarations.
+func (b *Builder[S]) RegisterOn(v *Validator) error {
+	if b.err != nil {
+		return b.err
+	}
+	tags := make(map[int]string, len(b.fields))
+	for index, spec := range b.fields {
+		rules := slices.Clone(spec.rules)
+		if spec.elems != nil {
+			rules = append(append(rules, "dive"), spec.elems...)
+		}
+		tags[index] = strings.Join(rules, ",")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.fieldTags[b.typ] = tags
+	v.builderStructs[b.typ] = slices.Clone(b.validators)
+	v.invalidatePlans()
+	return nil
+}
+
+// fieldIndex finds the field of S whose address get returns. get is a func(*S) *T.
+func (b *Builder[S]) fieldIndex(get interface{}) (int, error) {
+	fn := reflect.ValueOf(get)
+	if fn.IsNil() {
+		return 0, fmt.Errorf("field accessor for %s must not be nil", b.typ)
+	}
+
+	s := reflect.New(b.typ)
+	ptr := fn.Call([]reflect.Value{s})[0]
+	if !ptr.IsNil() && ptr.Pointer() >= s.Pointer() {
+		offset, typ := ptr.Pointer()-s.Pointer(), ptr.Type().Elem()
+		for i := 0; i < b.typ.NumField(); i++ {
+			if field := b.typ.Field(i); field.Offset == offset && field.Type == typ && field.IsExported() {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("field accessor must return the address of an exported field declared in %s", b.typ)
+}
+
+// Tag declares the rules of a tag fragment, for rules without a constructor,
+// e.g. Tag[string]("required_if=Kind card") or Tag[string]("email|e164")
+func Tag[T any](tag string) RuleSpec[T] {
+	return RuleSpec[T]{tag: tag}
+}
+
+// ruleSpec declares a rule with its parameters, quoting them as needed
+func ruleSpec[T any](name string, params ...string) RuleSpec[T] {
+	if len(params) == 0 {
+		return RuleSpec[T]{tag: name}
+	}
+	quoted := make([]string, len(params))
+	for i, param := range params {
+		quoted[i] = quoteParam(param)
+	}
+	return RuleSpec[T]{tag: name + "=" + strings.Join(quoted, " ")}
+}
+
+// quoteParam writes a parameter so that the tag parser reads it back unchanged
+func quoteParam(param string) string {
+	if param != "" && !strings.ContainsAny(param, " ,|()'\\") {
+		return param
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(param) + "'"
+}
+
+// Number is the set of types the sign rules accept
+type Number interface {
+	int | int32 | int64 | float32 | float64
+}
+
+// Required declares the required rule
+func Required[T any]() RuleSpec[T] { return ruleSpec[T]("required") }
+
+// NonNull declares the non-null rule
+func NonNull[T any]() RuleSpec[T] { return ruleSpec[T]("non-null") }
+
+// NonBlank declares the non-blank rule
+func NonBlank() RuleSpec[string] { return ruleSpec[string]("non-blank") }
+
+// NonEmpty declares the non-empty rule
+func NonEmpty[T any]() RuleSpec[T] { return ruleSpec[T]("non-empty") }
+
+// Min declares the min rule
+func Min(n int) RuleSpec[int] { return ruleSpec[int]("min", strconv.Itoa(n)) }
+
+// Max declares the max rule
+func Max(n int) RuleSpec[int] { return ruleSpec[int]("max", strconv.Itoa(n)) }
+
+// Email declares the email rule
+func Email() RuleSpec[string] { return ruleSpec[string]("email") }
+
+// E164 declares the e164 rule
+func E164() RuleSpec[string] { return ruleSpec[string]("e164") }
+
+// IsTrue declares the isTrue rule
+func IsTrue() RuleSpec[bool] { return ruleSpec[bool]("isTrue") }
+
+// Positive declares the positive rule
+func Positive[T Number]() RuleSpec[T] { return ruleSpec[T]("positive") }
+
+// Negative declares the negative rule
+func Negative[T Number]() RuleSpec[T] { return ruleSpec[T]("negative") }
+
+// PositiveOrZero declares the positiveOrZero rule
+func PositiveOrZero[T Number]() RuleSpec[T] { return ruleSpec[T]("positiveOrZero") }
+
+// NegativeOrZero declares the negativeOrZero rule
+func NegativeOrZero[T Number]() RuleSpec[T] { return ruleSpec[T]("negativeOrZero") }
+
+// Size declares the size rule, for strings, slices, arrays and maps
+func Size[T any](n int) RuleSpec[T] { return ruleSpec[T]("size", strconv.Itoa(n)) }
+
+// MinSize declares the minSize rule, for strings, slices, arrays and maps
+func MinSize[T any](n int) RuleSpec[T] { return ruleSpec[T]("minSize", strconv.Itoa(n)) }
+
+// MaxSize declares the maxSize rule, for strings, slices, arrays and maps
+func MaxSize[T any](n int) RuleSpec[T] { return ruleSpec[T]("maxSize", strconv.Itoa(n)) }
+
+// Date declares the date rule with the layout of string dates
+func Date(layout string) RuleSpec[string] { return ruleSpec[string]("date", layout) }
+
+// After declares the after rule; the optional layout parses string dates and date
+func After[T any](date string, layout ...string) RuleSpec[T] {
+	return ruleSpec[T]("after", append([]string{date}, layout...)...)
+}
+
+// Before declares the before rule; the optional layout parses string dates and date
+func Before[T any](date string, layout ...string) RuleSpec[T] {
+	return ruleSpec[T]("before", append([]string{date}, layout...)...)
+}
+
+// Between declares the between rule; the optional layout parses string dates, start and end
+func Between[T any](start, end string, layout ...string) RuleSpec[T] {
+	return ruleSpec[T]("between", append([]string{start, end}, layout...)...)
+}
+
+// Past declares the past rule; the optional layout parses string dates
+func Past[T any](layout ...string) RuleSpec[T] { return ruleSpec[T]("past", layout...) }
+
+// Future declares the future rule; the optional layout parses string dates
+func Future[T any](layout ...string) RuleSpec[T] { return ruleSpec[T]("future", layout...) }
+
+// MinDuration declares the minDuration rule
+func MinDuration(d time.Duration) RuleSpec[time.Duration] {
+	return ruleSpec[time.Duration]("minDuration", d.String())
+}
+
+// MaxDuration declares the maxDuration rule
+func MaxDuration(d time.Duration) RuleSpec[time.Duration] {
+	return ruleSpec[time.Duration]("maxDuration", d.String())
+}
+
+// EqField declares the eqfield rule comparing with the field at path
+func EqField[T any](path string) RuleSpec[T] { return ruleSpec[T]("eqfield", path) }
+
+// NeField declares the nefield rule comparing with the field at path
+func NeField[T any](path string) RuleSpec[T] { return ruleSpec[T]("nefield", path) }
+
+// GtField declares the gtfield rule comparing with the field at path
+func GtField[T any](path string) RuleSpec[T] { return ruleSpec[T]("gtfield", path) }
+
+// GteField declares the gtefield rule comparing with the field at path
+func GteField[T any](path string) RuleSpec[T] { return ruleSpec[T]("gtefield", path) }
+
+// LtField declares the ltfield rule comparing with the field at path
+func LtField[T any](path string) RuleSpec[T] { return ruleSpec[T]("ltfield", path) }
+
+// LteField declares the ltefield rule comparing with the field at path
+func LteField[T any](path string) RuleSpec[T] { return ruleSpec[T]("ltefield", path) }
+
+// OmitEmpty declares the omitempty modifier; use Optional for rules of the value a pointer points to
+func OmitEmpty[T any]() RuleSpec[T] { return ruleSpec[T]("omitempty") }
+
+// Redact declares the redact modifier
+func Redact[T any]() RuleSpec[T] { return ruleSpec[T]("redact") }
+
+// Groups declares the groups the field belongs to
+func Groups[T any](groups ...string) RuleSpec[T] { return ruleSpec[T]("groups", groups...) }
+
+// Msg replaces the message of the preceding rule
+func Msg[T any](message string) RuleSpec[T] { return ruleSpec[T]("msg", message) }
@@ -0,0 +1,140 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// testGeneratedUser stands for a generated type that cannot carry tags
+type testGeneratedUser struct {
+	Name     string
+	Email    string
+	Age      int
+	Password string
+	Confirm  string
+	Tags     []string
+	Joined   string
+}
+
+// testTaggedUser declares with tags the rules declared for testGeneratedUser
+type testTaggedUser struct {
+	Name     string   `validate:"required,msg='Please, enter a name'"`
+	Email    string   `validate:"required,email"`
+	Age      int      `validate:"min=18"`
+	Password string   `validate:"required"`
+	Confirm  string   `validate:"eqfield=Password"`
+	Tags     []string `validate:"dive,minSize=2"`
+	Joined   string   `validate:"omitempty,after='2024-01-01 00:00' '2006-01-02 15:04'"`
+}
+
+// Test that rules declared with a Builder report the same errors as tags
+func TestBuilder(t *testing.T) {
+	v := New()
+	b := For[testGeneratedUser]()
+	Field(b, func(u *testGeneratedUser) *string { return &u.Name }, Required[string](), Msg[string]("Please, enter a name"))
+	Field(b, func(u *testGeneratedUser) *string { return &u.Email }, Required[string]())
+	Field(b, func(u *testGeneratedUser) *int { return &u.Age }, Min(18))
+	Field(b, func(u *testGeneratedUser) *string { return &u.Password }, Required[string]())
+	Field(b, func(u *testGeneratedUser) *string { return &u.Confirm }, EqField[string]("Password"))
+	Elements(b, func(u *testGeneratedUser) *[]string { return &u.Tags }, MinSize[string](2))
+	Field(b, func(u *testGeneratedUser) *string { return &u.Joined }, OmitEmpty[string](), After[string]("2024-01-01 00:00", "2006-01-02 15:04"))
+	Field(b, func(u *testGeneratedUser) *string { return &u.Email }, Email())
+	b.Struct(func(r *Report, u testGeneratedUser) {
+		if u.Name == u.Password && u.Name != "" {
+			r.Add("Password", "must differ from the name")
+		}
+	})
+	if err := b.RegisterOn(v); err != nil {
+		t.Fatalf("RegisterOn: %v", err)
+	}
+
+	generated := testGeneratedUser{Email: "x", Age: 17, Password: "a", Confirm: "b", Tags: []string{"go", "x"}, Joined: "2023-12-31 23:00"}
+	tagged := testTaggedUser(generated)
+	got, want := v.Validate(generated), v.Validate(tagged)
+	if len(got) != len(want) || len(got) != 6 {
+		t.Fatalf("expected the errors of the tags %v, got %v", want, got)
+	}
+	for i := range want {
+		want[i].Namespace = strings.Replace(want[i].Namespace, "testTaggedUser", "testGeneratedUser", 1)
+		if got[i].Error() != want[i].Error() || got[i].Code != want[i].Code || got[i].Namespace != want[i].Namespace {
+			t.Errorf("expected %+v, got %+v", want[i], got[i])
+		}
+	}
+
+	errs := v.Validate(testGeneratedUser{Name: "same", Email: "a@b.co", Age: 18, Password: "same", Confirm: "same"})
+	assertErrors(t, errs, []string{"Password: must differ from the name"})
+
+	// Registering again replaces the struct validations instead of adding to them
+	if err := b.RegisterOn(v); err != nil {
+		t.Fatalf("RegisterOn: %v", err)
+	}
+	errs = v.Validate(testGeneratedUser{Name: "same", Email: "a@b.co", Age: 18, Password: "same", Confirm: "same"})
+	assertErrors(t, errs, []string{"Password: must differ from the name"})
+	if errs := Validate(generated); errs.HasErrors() {
+		t.Errorf("expected the default Validator to be unaffected, got %v", errs)
+	}
+}
+
+// Test reporting invalid declarations
+func TestBuilderErrors(t *testing.T) {
+	type embedded struct{ Inner string }
+	type outer struct {
+		embedded
+		Name  string
+		count int
+	}
+	promoted, unexported := For[outer](), For[outer]()
+	Field(promoted, func(o *outer) *string { return &o.Inner }, Required[string]())
+	Field(unexported, func(o *outer) *int { return &o.count }, Required[int]())
+	tests := []struct {
+		name string
+		b    interface{ RegisterOn(*Validator) error }
+		want string
+	}{
+		{"not a struct", For[int](), "rules can only be declared for structs"},
+		{"promoted field", promoted, "address of an exported field"},
+		{"unexported field", unexported, "address of an exported field"},
+		{"nil struct validator", For[outer]().Struct(nil), "must not be nil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.b.RegisterOn(New()); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// Malformed rules are reported like malformed tags
+	v := New()
+	b := For[outer]()
+	Field(b, func(o *outer) *string { return &o.Name }, Tag[string]("minSize=abc"))
+	if err := b.RegisterOn(v); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Compile(outer{}); err == nil || !strings.Contains(err.Error(), "invalid validate tag on validator.outer.Name") {
+		t.Errorf("expected a tag error, got %v", err)
+	}
+}
+
+type testGeneratedAccount struct {
+	Age     *int
+	Balance *float64
+}
+
+// Test rules of the values optional pointer fields point to
+func TestBuilderOptional(t *testing.T) {
+	v := New()
+	b := For[testGeneratedAccount]()
+	Optional(b, func(a *testGeneratedAccount) **int { return &a.Age }, Min(18))
+	Optional(b, func(a *testGeneratedAccount) **float64 { return &a.Balance }, PositiveOrZero[float64]())
+	if err := b.RegisterOn(v); err != nil {
+		t.Fatal(err)
+	}
+
+	age, balance := 17, -1.0
+	assertErrors(t, v.Validate(testGeneratedAccount{}), nil)
+	assertErrors(t, v.Validate(testGeneratedAccount{Age: &age, Balance: &balance}), []string{
+		"Age: Age must be at least 18",
+		"Balance: Balance must be positive or zero",
+	})
+}
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+)
+
+// Check validates s with the default Validator, returning an error instead of ValidationErrors
+func Check(s interface{}) error {
+	return defaultValidator.Check(s)
+}
+
+// CheckContext is like Check, passing ctx to context-aware rules
+func CheckContext(ctx context.Context, s interface{}) error {
+	return defaultValidator.CheckContext(ctx, s)
+}
+
+// Check validates s like Validate and returns nil when it is valid, its ValidationErrors
+// when it is not, and an *InvalidInputError when s cannot be validated at all.
+func (v *Validator) Check(s interface{}) error {
+	return v.CheckGroupsContext(context.Background(), s)
+}
+
+// CheckContext is like Check, passing ctx to context-aware rules
+func (v *Validator) CheckContext(ctx context.Context, s interface{}) error {
+	return v.CheckGroupsContext(ctx, s)
+}
+
+// CheckGroupsContext is like Check, validating only the fields of the given groups
+func (v *Validator) CheckGroupsContext(ctx context.Context, s interface{}, groups ...string) error {
+	val, err := inputValue(s)
+	if err != nil {
+		return err
+	}
+	if errs := v.validateGroups(ctx, val, groups); errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// inputValue dereferences the value passed to Validate, which must be a struct or a
+// slice, array or map of structs or struct pointers, possibly behind pointers
+func inputValue(s interface{}) (reflect.Value, error) {
+	val := reflect.ValueOf(s)
+	if !val.IsValid() {
+		return reflect.Value{}, &InvalidInputError{}
+	}
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return reflect.Value{}, &InvalidInputError{Type: val.Type()}
+		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Struct:
+		return val, nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if elem := val.Type().Elem(); elem.Kind() == reflect.Struct || (elem.Kind() == reflect.Ptr && elem.Elem().Kind() == reflect.Struct) {
+			return val, nil
+		}
+	}
+	return reflect.Value{}, &InvalidInputError{Type: val.Type()}
+}
@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testCheckItem struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1"`
+}
+
+// Test validating nil, non-struct and collection inputs without panicking
+func TestValidateInput(t *testing.T) {
+	var nilItem *testCheckItem
+	for _, input := range []interface{}{nil, nilItem, 42, []string{"a"}} {
+		errs := Validate(input)
+		if len(errs) != 1 || errs[0].Code != CodeInvalidInput {
+			t.Errorf("Validate(%#v): expected an invalid input error, got %v", input, errs)
+		}
+	}
+	assertErrors(t, Validate(nilItem), []string{"validator: cannot validate a nil *validator.testCheckItem"})
+
+	items := []*testCheckItem{{SKU: "a", Quantity: 1}, nil, {Quantity: 0}}
+	errs := Validate(&items)
+	assertErrors(t, errs, []string{
+		"[2].SKU: [2].SKU is required",
+		"[2].Quantity: [2].Quantity must be at least 1",
+	})
+	if errs[0].Namespace != "testCheckItem[2].SKU" || errs[0].JSONPath != "$[2].sku" {
+		t.Errorf("unexpected paths %+v", errs[0])
+	}
+
+	byID := map[string]testCheckItem{"b": {SKU: "b"}, "a": {Quantity: 2}}
+	assertErrors(t, Validate(byID), []string{
+		"[a].SKU: [a].SKU is required",
+		"[b].Quantity: [b].Quantity must be at least 1",
+	})
+}
+
+// Test the error-returning variant
+func TestCheck(t *testing.T) {
+	if err := Check(testCheckItem{SKU: "a", Quantity: 1}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	var errs ValidationErrors
+	if err := CheckContext(context.Background(), testCheckItem{}); !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("expected ValidationErrors, got %v", err)
+	}
+
+	var invalid *InvalidInputError
+	if err := Check((*testCheckItem)(nil)); !errors.As(err, &invalid) || invalid.Type == nil {
+		t.Errorf("expected an InvalidInputError, got %v", err)
+	}
+	if err := Check("text"); !errors.As(err, &invalid) || invalid.Error() != "validator: cannot validate string, expected a struct, a pointer to a struct or a collection of structs" {
+		t.Errorf("expected an InvalidInputError, got %v", err)
+	}
+}
+
+// Test validating standalone values
+func TestVar(t *testing.T) {
+	assertErrors(t, Var("", "required,email"), []string{
+		"Value: Value is required",
+		"Value: Value is not a valid email",
+	})
+	if errs := Var("a@b.co", "required,email"); errs.HasErrors() {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+	assertErrors(t, Var(nil, "required"), []string{"Value: Value is required"})
+	assertErrors(t, Var([]int{1, 0}, "dive,positive"), []string{"Value[1]: Value[1] must be positive"})
+	assertErrors(t, Var(testCheckItem{}, "required"), []string{
+		"Value: Value is required",
+		"Value.SKU: Value.SKU is required",
+		"Value.Quantity: Value.Quantity must be at least 1",
+	})
+
+	assertErrors(t, VarWithField("secret", "secrets", "eqfield=Other"), []string{"Value: Value must be equal to Other"})
+	if errs := VarWithField(10, 5, "gtfield=Other"); errs.HasErrors() {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+
+	errs := Var(5, "min=")
+	if len(errs) != 1 || errs[0].Code != CodeInvalidTag {
+		t.Errorf("expected a tag error, got %v", errs)
+	}
+	assertErrors(t, Var("a", "requierd"), []string{`Value: invalid validate tag at offset 0: unknown validation rule "requierd"`})
+}
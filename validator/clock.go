@@ -0,0 +1,44 @@
+package validator
+
+import "time"
+
+// Clock tells the date rules what the current time is
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts an ordinary function to the Clock interface
+type ClockFunc func() time.Time
+
+// Now implements the Clock interface for ClockFunc
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock reads the current time from the operating system
+var SystemClock Clock = ClockFunc(time.Now)
+
+// WithClock makes the date rules of the Validator read the current time from c,
+// which keeps rules such as past and future deterministic in tests
+func WithClock(c Clock) Option {
+	return func(v *Validator) {
+		v.clock = c
+	}
+}
+
+// WithLocation sets the time zone date rules use to interpret dates without a zone
+// and to decide what day "today" is. It defaults to time.Local (nil means UTC) and can be
+// overridden per field with the tz modifier, e.g. validate:"tz=America/Sao_Paulo,past=2006-01-02".
+func WithLocation(loc *time.Location) Option {
+	return func(v *Validator) {
+		if loc == nil {
+			loc = time.UTC
+		}
+		v.location = loc
+	}
+}
+
+// now returns the current time of the clock in the configured time zone
+func (e ruleEnv) now() time.Time {
+	return e.clock.Now().In(e.location)
+}
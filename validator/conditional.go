@@ -0,0 +1,169 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fieldCondition holds when every referenced field has its expected value,
+// e.g. the parameters "PaymentMethod card Country BR"
+type fieldCondition struct {
+	refs   []fieldRef
+	values []string
+}
+
+// compileFieldCondition resolves the field/value pairs of a condition
+func compileFieldCondition(env ruleEnv, params []string) (fieldCondition, error) {
+	if len(params) == 0 || len(params)%2 != 0 {
+		return fieldCondition{}, fmt.Errorf("expected field and value pairs, got %d parameter(s)", len(params))
+	}
+	var cond fieldCondition
+	for i := 0; i < len(params); i += 2 {
+		ref, err := resolveFieldRef(env, params[i])
+		if err != nil {
+			return fieldCondition{}, err
+		}
+		cond.refs = append(cond.refs, ref)
+		cond.values = append(cond.values, params[i+1])
+	}
+	return cond, nil
+}
+
+// holds reports whether every referenced field of parent has its expected value
+func (c fieldCondition) holds(parent reflect.Value) bool {
+	for i, ref := range c.refs {
+		value, ok := ref.lookup(parent)
+		if !ok || !matchesParam(value, c.values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String describes the condition for error messages, e.g. "PaymentMethod is card"
+func (c fieldCondition) String() string {
+	parts := make([]string, len(c.refs))
+	for i, ref := range c.refs {
+		parts[i] = ref.path + " is " + c.values[i]
+	}
+	return strings.Join(parts, " and ")
+}
+
+// matchesParam reports whether a field value, dereferenced, is written as param
+func matchesParam(value interface{}, param string) bool {
+	v := indirect(reflect.ValueOf(value))
+	if !v.IsValid() {
+		return param == "nil"
+	}
+	return fmt.Sprint(v.Interface()) == param
+}
+
+// conditionalRule checks the presence of a field depending on its siblings
+type conditionalRule struct {
+	// required is true for required_* rules and false for excluded_* rules
+	required bool
+	// negate applies the rule when the condition does not hold
+	negate  bool
+	message string
+}
+
+var (
+	requiredIfRule     = conditionalRule{required: true, message: "%s is required when %s"}
+	requiredUnlessRule = conditionalRule{required: true, negate: true, message: "%s is required unless %s"}
+	excludedIfRule     = conditionalRule{message: "%s must be empty when %s"}
+	excludedUnlessRule = conditionalRule{negate: true, message: "%s must be empty unless %s"}
+)
+
+// compile resolves the field/value pairs of the condition once
+func (r conditionalRule) compile(env ruleEnv, params []string) (checkFunc, error) {
+	cond, err := compileFieldCondition(env, params)
+	if err != nil {
+		return nil, err
+	}
+	return func(fc *FieldContext) error {
+		if cond.holds(fc.Parent) == r.negate || isEmpty(fc.Value) != r.required {
+			return nil
+		}
+		return fmt.Errorf(r.message, fc.Path, cond)
+	}, nil
+}
+
+// presenceRule requires a field depending on whether other fields are set
+type presenceRule struct {
+	// all requires every referenced field to match instead of any of them
+	all bool
+	// present applies the rule when the referenced fields are set rather than missing
+	present bool
+	message string
+}
+
+var (
+	requiredWithRule       = presenceRule{present: true, message: "%s is required when %s is present"}
+	requiredWithAllRule    = presenceRule{all: true, present: true, message: "%s is required when %s are present"}
+	requiredWithoutRule    = presenceRule{message: "%s is required when %s is missing"}
+	requiredWithoutAllRule = presenceRule{all: true, message: "%s is required when %s are missing"}
+)
+
+// compile resolves the referenced fields once
+func (r presenceRule) compile(env ruleEnv, params []string) (checkFunc, error) {
+	if len(params) == 0 {
+		return nil, fmt.Errorf("expected at least 1 field name")
+	}
+	refs := make([]fieldRef, len(params))
+	for i, param := range params {
+		ref, err := resolveFieldRef(env, param)
+		if err != nil {
+			return nil, err
+		}
+		refs[i] = ref
+	}
+	sep := " or "
+	if r.all {
+		sep = " and "
+	}
+	return func(fc *FieldContext) error {
+		if !isEmpty(fc.Value) {
+			return nil
+		}
+		var matched []string
+		for _, ref := range refs {
+			other, ok := ref.lookup(fc.Parent)
+			if (ok && !isEmpty(other)) == r.present {
+				matched = append(matched, ref.path)
+			} else if r.all {
+				return nil
+			}
+		}
+		if len(matched) == 0 {
+			return nil
+		}
+		return fmt.Errorf(r.message, fc.Path, strings.Join(matched, sep))
+	}, nil
+}
+
+// whenPlan applies the rules following a "when" guard only while its condition holds
+type whenPlan struct {
+	cond  func(parent reflect.Value) bool
+	rules *rulePlan
+}
+
+// compileWhen compiles the condition of a "when" guard: either field/value pairs
+// that must all match, or a single field that must be present
+func compileWhen(env ruleEnv, params []string) (func(parent reflect.Value) bool, error) {
+	if len(params) == 1 {
+		ref, err := resolveFieldRef(env, params[0])
+		if err != nil {
+			return nil, err
+		}
+		return func(parent reflect.Value) bool {
+			other, ok := ref.lookup(parent)
+			return ok && !isEmpty(other)
+		}, nil
+	}
+	cond, err := compileFieldCondition(env, params)
+	if err != nil {
+		return nil, err
+	}
+	return cond.holds, nil
+}
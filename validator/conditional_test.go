@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+type testPayment struct {
+	PaymentMethod string
+	Country       string
+	CardNumber    string `validate:"required_if=PaymentMethod card,when=PaymentMethod card,minSize=12"`
+	IBAN          string `validate:"required_unless=PaymentMethod card"`
+	CVV           string `validate:"required_if=PaymentMethod card Country US"`
+	Voucher       string `validate:"excluded_if=PaymentMethod card"`
+	Email         string `validate:"required_without=Phone"`
+	Phone         string
+	Extension     string `validate:"when=Phone,maxSize=4"`
+	Contact       *string
+	ContactName   string `validate:"required_with=Contact Extension"`
+	Installments  int
+	Months        int `validate:"required_if=Installments 3"`
+}
+
+// Test conditional presence rules and the when guard
+func TestConditionalRules(t *testing.T) {
+	assertErrors(t, Validate(testPayment{
+		PaymentMethod: "card",
+		Country:       "US",
+		CardNumber:    "4111111111111111",
+		CVV:           "123",
+		Email:         "john@example.com",
+		Extension:     "123456", // only checked when Phone is set
+		ContactName:   "John",
+	}), nil)
+
+	contact := ""
+	assertErrors(t, Validate(testPayment{
+		PaymentMethod: "card",
+		Country:       "US",
+		Voucher:       "FREE",
+		Phone:         "555",
+		Extension:     "123456",
+		Contact:       &contact,
+		Installments:  3,
+	}), []string{
+		"CardNumber: CardNumber is required when PaymentMethod is card",
+		"CardNumber: CardNumber must have at least 12 elements",
+		"CVV: CVV is required when PaymentMethod is card and Country is US",
+		"Voucher: Voucher must be empty when PaymentMethod is card",
+		"Extension: Extension must have at most 4 elements",
+		"ContactName: ContactName is required when Contact or Extension is present",
+		"Months: Months is required when Installments is 3",
+	})
+
+	assertErrors(t, Validate(testPayment{PaymentMethod: "pix", CardNumber: "123"}), []string{
+		"IBAN: IBAN is required unless PaymentMethod is card",
+		"Email: Email is required when Phone is missing",
+	})
+}
+
+type testBadConditions struct {
+	Method string
+	Pairs  string `validate:"required_if=Method"`
+	Ref    string `validate:"required_with=Missing"`
+	Guard  string `validate:"when=Method card Pairs,email"`
+}
+
+// Test conditional rules with malformed parameters
+func TestConditionalRulesTagErrors(t *testing.T) {
+	err := New().Compile(testBadConditions{})
+	for _, expected := range []string{
+		"Pairs at offset 0: required_if: expected field and value pairs, got 1 parameter(s)",
+		`Ref at offset 0: required_with: unknown field "Missing" in validator.testBadConditions`,
+		"Guard at offset 0: when: expected field and value pairs, got 3 parameter(s)",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error containing %q, got %v", expected, err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+)
+
+// FieldContext describes the field being validated to a FieldRule.
+// The engine reuses it between rules, so rules must not retain it after returning.
+type FieldContext struct {
+	ctx context.Context
+
+	// Value is the value under validation: the field itself, or an element or key after a dive
+	Value interface{}
+	// Path is the full path of the value, e.g. Address.ZipCode or Tags[3]
+	Path string
+	// Field is the struct field the tag is attached to
+	Field reflect.StructField
+	// Tag is the full validate tag of the field
+	Tag string
+	// Parent is the struct holding the field
+	Parent reflect.Value
+	// Top is the struct passed to Validate
+	Top reflect.Value
+	// Rule and Params are the name and parameters of the rule being applied
+	Rule   string
+	Params []string
+}
+
+// Context returns the context the validation was started with
+func (fc *FieldContext) Context() context.Context {
+	if fc.ctx == nil {
+		return context.Background()
+	}
+	return fc.ctx
+}
+
+// FieldRule is a rule with access to the context of the field being validated
+type FieldRule func(fc *FieldContext) error
+
+// AdaptRule turns a Rule into a FieldRule, passing it the field path, value and parameters
+func AdaptRule(rule Rule) FieldRule {
+	return func(fc *FieldContext) error {
+		return rule(fc.Path, fc.Value, fc.Params...)
+	}
+}
@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testTenantKey struct{}
+
+type testAccount struct {
+	Tenant string
+	Owner  struct {
+		Email string `validate:"required,sameTenant=@"`
+	}
+	Aliases []string `validate:"dive,sameTenant=@"`
+}
+
+// Test that field rules see the context, parent, top-level value and parameters
+func TestFieldRuleContext(t *testing.T) {
+	v := New()
+	var seen []*FieldContext
+	err := v.RegisterFieldRule("sameTenant", func(fc *FieldContext) error {
+		copied := *fc
+		seen = append(seen, &copied)
+		tenant := fc.Top.FieldByName("Tenant").String()
+		if want, _ := fc.Context().Value(testTenantKey{}).(string); want != tenant {
+			return fmt.Errorf("%s belongs to tenant %s, not %s", fc.Path, tenant, want)
+		}
+		if fc.Value != fc.Params[0]+tenant && fc.Value != "admin"+fc.Params[0]+tenant {
+			return fmt.Errorf("%s must end with %s%s", fc.Path, fc.Params[0], tenant)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account := testAccount{Tenant: "acme", Aliases: []string{"@acme", "bob@acme"}}
+	account.Owner.Email = "admin@acme"
+	ctx := context.WithValue(context.Background(), testTenantKey{}, "acme")
+
+	assertErrors(t, v.ValidateContext(ctx, &account), []string{
+		"Aliases[1]: Aliases[1] must end with @acme",
+	})
+	if len(seen) != 3 {
+		t.Fatalf("expected 3 rule calls, got %d", len(seen))
+	}
+	owner := seen[0]
+	if owner.Path != "Owner.Email" || owner.Field.Name != "Email" || owner.Tag != "required,sameTenant=@" {
+		t.Errorf("unexpected field context %+v", owner)
+	}
+	if owner.Rule != "sameTenant" || len(owner.Params) != 1 || owner.Params[0] != "@" {
+		t.Errorf("unexpected rule %s %v", owner.Rule, owner.Params)
+	}
+	if owner.Parent.Type() != reflect.TypeOf(account.Owner) {
+		t.Errorf("expected the nested struct as parent, got %s", owner.Parent.Type())
+	}
+
+	other := context.WithValue(context.Background(), testTenantKey{}, "other")
+	assertErrors(t, v.ValidateContext(other, &account), []string{
+		"Owner.Email: Owner.Email belongs to tenant acme, not other",
+		"Aliases[0]: Aliases[0] belongs to tenant acme, not other",
+		"Aliases[1]: Aliases[1] belongs to tenant acme, not other",
+	})
+}
+
+// Test that plain rules registered as field rules behave as before
+func TestAdaptRule(t *testing.T) {
+	v := New(WithFieldRule("even", AdaptRule(evenRule)))
+	assertErrors(t, v.Validate(struct {
+		N int `validate:"even"`
+	}{N: 3}), []string{"N: N must be even"})
+
+	if err := v.RegisterFieldRule("bad name", AdaptRule(evenRule)); err == nil {
+		t.Error("expected an invalid rule name error")
+	}
+	if err := v.RegisterFieldRule("odd", nil); err == nil {
+		t.Error("expected a nil rule error")
+	}
+}
@@ -0,0 +1,254 @@
+package validator
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// fieldRef locates another field of the struct holding the validated field,
+// e.g. "Password" or a dotted path into nested structs such as "Period.Start"
+type fieldRef struct {
+	path    string // path as reported in messages, using the field names of the Validator
+	indexes [][]int
+}
+
+// resolveFieldRef resolves a field path against the struct of env when a plan is compiled
+func resolveFieldRef(env ruleEnv, path string) (fieldRef, error) {
+	if env.resolve != nil {
+		return fieldRef{path: path}, env.resolve(path)
+	}
+	if env.structType == nil {
+		return fieldRef{}, fmt.Errorf("can only be used on struct fields")
+	}
+	var ref fieldRef
+	typ := env.structType
+	for _, name := range strings.Split(path, ".") {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return fieldRef{}, fmt.Errorf("%q is not a struct field path", path)
+		}
+		field, ok := typ.FieldByName(name)
+		if !ok || !field.IsExported() {
+			return fieldRef{}, fmt.Errorf("unknown field %q in %s", name, typ)
+		}
+		ref.indexes = append(ref.indexes, field.Index)
+		ref.path = joinPath(ref.path, env.fieldName.fieldName(field))
+		typ = field.Type
+	}
+	return ref, nil
+}
+
+// lookup returns the referenced field of parent, or false when a nil pointer is on the way
+func (r fieldRef) lookup(parent reflect.Value) (interface{}, bool) {
+	value := parent
+	for _, index := range r.indexes {
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return nil, false
+			}
+			value = value.Elem()
+		}
+		var err error
+		if value, err = value.FieldByIndexErr(index); err != nil {
+			return nil, false
+		}
+	}
+	return value.Interface(), true
+}
+
+// fieldComparison compares the validated field with another field of the same struct
+type fieldComparison struct {
+	accept  func(cmp int) bool
+	message string
+}
+
+var (
+	eqFieldRule  = fieldComparison{func(cmp int) bool { return cmp == 0 }, "%s must be equal to %s"}
+	neFieldRule  = fieldComparison{func(cmp int) bool { return cmp != 0 }, "%s must not be equal to %s"}
+	gtFieldRule  = fieldComparison{func(cmp int) bool { return cmp > 0 }, "%s must be greater than %s"}
+	gteFieldRule = fieldComparison{func(cmp int) bool { return cmp >= 0 }, "%s must be greater than or equal to %s"}
+	ltFieldRule  = fieldComparison{func(cmp int) bool { return cmp < 0 }, "%s must be less than %s"}
+	lteFieldRule = fieldComparison{func(cmp int) bool { return cmp <= 0 }, "%s must be less than or equal to %s"}
+)
+
+// compile resolves the referenced field once
+func (c fieldComparison) compile(env ruleEnv, params []string) (checkFunc, error) {
+	if err := expectParams(params, 1); err != nil {
+		return nil, err
+	}
+	ref, err := resolveFieldRef(env, params[0])
+	if err != nil {
+		return nil, err
+	}
+	return func(fc *FieldContext) error {
+		other, ok := ref.lookup(fc.Parent)
+		if !ok {
+			return nil
+		}
+		cmp, ok := compareValues(fc.Value, other)
+		if !ok {
+			// Values that cannot be ordered can still be checked for equality
+			if c.accept(0) == c.accept(1) {
+				return reasonf(reasonIncomparable, fc.Path, "%s cannot be compared with %s", fc.Path, ref.path)
+			}
+			cmp = 1
+			if reflect.DeepEqual(fc.Value, other) {
+				cmp = 0
+			}
+		}
+		if !c.accept(cmp) {
+			return fmt.Errorf(c.message, fc.Path, ref.path)
+		}
+		return nil
+	}, nil
+}
+
+// dateFieldComparison compares the validated date with a date held by another field
+type dateFieldComparison struct {
+	accept  func(cmp int) bool
+	message string
+}
+
+var (
+	afterFieldRule  = dateFieldComparison{func(cmp int) bool { return cmp > 0 }, "%s must be after %s"}
+	beforeFieldRule = dateFieldComparison{func(cmp int) bool { return cmp < 0 }, "%s must be before %s"}
+)
+
+// compile resolves the referenced field once; the optional second parameter
+// is the format of string dates
+func (c dateFieldComparison) compile(env ruleEnv, params []string) (checkFunc, error) {
+	if err := expectParamRange(params, 1, 2); err != nil {
+		return nil, err
+	}
+	ref, err := resolveFieldRef(env, params[0])
+	if err != nil {
+		return nil, err
+	}
+	format := formatParam(params, 1)
+	return func(fc *FieldContext) error {
+		value, ok, err := resolveDate(fc.Path, fc.Value, format, env.location)
+		if err != nil || !ok {
+			return err
+		}
+		raw, found := ref.lookup(fc.Parent)
+		if !found {
+			return nil
+		}
+		other, ok, err := resolveDate(ref.path, raw, format, env.location)
+		if err != nil || !ok {
+			return err
+		}
+		if !c.accept(value.Compare(other)) {
+			return fmt.Errorf(c.message, fc.Path, ref.path)
+		}
+		return nil
+	}, nil
+}
+
+// compareValues orders two numbers, strings or dates, returning -1, 0 or +1
+func compareValues(a, b interface{}) (int, bool) {
+	if ta, ok := timeValue(a); ok {
+		if tb, ok := timeValue(b); ok {
+			return ta.Compare(tb), true
+		}
+		return 0, false
+	}
+
+	va, vb := indirect(reflect.ValueOf(a)), indirect(reflect.ValueOf(b))
+	if !va.IsValid() || !vb.IsValid() {
+		return 0, false
+	}
+	if va.Kind() == reflect.String && vb.Kind() == reflect.String {
+		return strings.Compare(va.String(), vb.String()), true
+	}
+	return compareNumbers(va, vb)
+}
+
+// timeValue extracts a time.Time from a value or a non-nil pointer
+func timeValue(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v != nil {
+			return *v, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// numberKind classifies the kinds of numbers compareNumbers orders
+type numberKind int
+
+const (
+	notNumber numberKind = iota
+	signedNumber
+	unsignedNumber
+	floatNumber
+)
+
+// numberKindOf returns the number kind of v
+func numberKindOf(v reflect.Value) numberKind {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return signedNumber
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return unsignedNumber
+	case reflect.Float32, reflect.Float64:
+		return floatNumber
+	}
+	return notNumber
+}
+
+// compareNumbers orders two integers or floats. Integers are compared exactly, as
+// float64 cannot represent every integer above 2^53; floats are compared as float64.
+func compareNumbers(a, b reflect.Value) (int, bool) {
+	ka, kb := numberKindOf(a), numberKindOf(b)
+	switch {
+	case ka == notNumber || kb == notNumber:
+		return 0, false
+	case ka == floatNumber || kb == floatNumber:
+		return cmp.Compare(floatValue(a, ka), floatValue(b, kb)), true
+	case ka == signedNumber && kb == signedNumber:
+		return cmp.Compare(a.Int(), b.Int()), true
+	case ka == unsignedNumber && kb == unsignedNumber:
+		return cmp.Compare(a.Uint(), b.Uint()), true
+	case ka == signedNumber:
+		if a.Int() < 0 {
+			return -1, true
+		}
+		return cmp.Compare(uint64(a.Int()), b.Uint()), true
+	default:
+		if b.Int() < 0 {
+			return 1, true
+		}
+		return cmp.Compare(a.Uint(), uint64(b.Int())), true
+	}
+}
+
+// floatValue converts a number of kind k to float64
+func floatValue(v reflect.Value, k numberKind) float64 {
+	switch k {
+	case signedNumber:
+		return float64(v.Int())
+	case unsignedNumber:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// indirect dereferences pointers, returning an invalid value for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
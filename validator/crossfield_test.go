@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"testing"
+	"time"
+)
+
+type testPeriodRange struct {
+	Start time.Time
+	End   time.Time `validate:"afterfield=Start"`
+}
+
+type testBooking struct {
+	Password        string
+	PasswordConfirm string `validate:"eqfield=Password"`
+	Username        string `validate:"nefield=Password"`
+	MinGuests       int
+	MaxGuests       int64 `validate:"gtefield=MinGuests"`
+	Discount        float64
+	Price           uint `validate:"gtfield=Discount"`
+	CheckIn         string
+	CheckOut        string `validate:"afterfield=CheckIn 2006-01-02"`
+	Deadline        string `validate:"beforefield=Period.End 2006-01-02"`
+	Period          *testPeriodRange
+	Nights          []int `validate:"dive,ltefield=MaxGuests"`
+}
+
+// Test cross-field comparisons on numbers, strings and dates
+func TestCrossFieldRules(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	valid := testBooking{
+		Password:        "secret",
+		PasswordConfirm: "secret",
+		Username:        "john",
+		MinGuests:       2,
+		MaxGuests:       2,
+		Discount:        9.5,
+		Price:           10,
+		CheckIn:         "2024-03-01",
+		CheckOut:        "2024-03-05",
+		Deadline:        "2024-03-05",
+		Period:          &testPeriodRange{Start: start, End: start.AddDate(0, 0, 7)},
+		Nights:          []int{1, 2},
+	}
+	assertErrors(t, Validate(valid), nil)
+
+	invalid := valid
+	invalid.PasswordConfirm = "secrets"
+	invalid.Username = "secret"
+	invalid.MaxGuests = 1
+	invalid.Price = 9
+	invalid.CheckOut = "2024-03-01"
+	invalid.Deadline = "2024-03-08"
+	invalid.Period = &testPeriodRange{Start: start, End: start}
+	invalid.Nights = []int{1, 3}
+	assertErrors(t, Validate(invalid), []string{
+		"PasswordConfirm: PasswordConfirm must be equal to Password",
+		"Username: Username must not be equal to Password",
+		"MaxGuests: MaxGuests must be greater than or equal to MinGuests",
+		"Price: Price must be greater than Discount",
+		"CheckOut: CheckOut must be after CheckIn",
+		"Deadline: Deadline must be before Period.End",
+		"Period.End: Period.End must be after Start",
+		"Nights[1]: Nights[1] must be less than or equal to MaxGuests",
+	})
+
+	// A nil pointer on the way to the referenced field skips the comparison
+	invalid.Period = nil
+	assertErrors(t, Validate(invalid)[5:], []string{
+		"Nights[1]: Nights[1] must be less than or equal to MaxGuests",
+	})
+}
+
+// Test that unknown referenced fields are reported when the plan is compiled
+func TestCrossFieldUnknownField(t *testing.T) {
+	err := New().Compile(struct {
+		End string `validate:"gtfield=Begin"`
+	}{})
+	expected := `invalid validate tag on struct { End string "validate:\"gtfield=Begin\"" }.End at offset 0: gtfield: unknown field "Begin" in struct { End string "validate:\"gtfield=Begin\"" }`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error '%s', got '%v'", expected, err)
+	}
+}
+
+type testLargeIDs struct {
+	ID       int64   `validate:"eqfield=Other"`
+	Other    int64   `validate:"gtfield=Unsigned"`
+	Unsigned uint64  `validate:"gtfield=Negative"`
+	Negative int32   `validate:"ltfield=Ratio"`
+	Ratio    float64 `validate:"ltfield=Unsigned"`
+}
+
+// Test that integers above 2^53 and mixed signed and unsigned integers compare exactly
+func TestCrossFieldLargeIntegers(t *testing.T) {
+	assertErrors(t, Validate(testLargeIDs{
+		ID:       9007199254740993,
+		Other:    9007199254740992,
+		Unsigned: 9007199254740992,
+		Negative: -1,
+		Ratio:    0.5,
+	}), []string{
+		"ID: ID must be equal to Other",
+		"Other: Other must be greater than Unsigned",
+	})
+	assertErrors(t, Validate(testLargeIDs{
+		ID:       9007199254740993,
+		Other:    9007199254740993,
+		Unsigned: 18446744073709551615,
+		Negative: -1,
+		Ratio:    0.5,
+	}), []string{"Other: Other must be greater than Unsigned"})
+}
@@ -0,0 +1,144 @@
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reference dates of after, before and between may be relative to the clock:
+//
+//	expr   = base { offset }
+//	base   = "now" | "today" | "yesterday" | "tomorrow" | "startOfWeek" | "startOfMonth" | "startOfYear"
+//	offset = ("+" | "-") digits unit
+//	unit   = "y" | "M" (months) | "w" | "d" | "h" | "m" (minutes) | "s"
+//
+// e.g. before=today-18y, before=today+30d, after=startOfMonth. Bases other than now
+// are midnight in the validator's location; expressions are evaluated at validation time.
+
+// relativeBases lists the bases of a relative date, longest first so prefixes do not shadow them
+var relativeBases = []string{"startOfMonth", "startOfWeek", "startOfYear", "yesterday", "tomorrow", "today", "now"}
+
+// dateRef is a reference date: either a literal date or an expression relative to the clock
+type dateRef struct {
+	fixed    time.Time
+	relative *relativeDate
+}
+
+// relativeDate is a parsed relative date expression
+type relativeDate struct {
+	base    string
+	offsets []dateOffset
+}
+
+// dateOffset shifts a relative date by n units
+type dateOffset struct {
+	n    int
+	unit byte
+}
+
+// parseDateRef parses a reference date, either relative or literal in the given format
+func parseDateRef(expr, format string, loc *time.Location) (dateRef, error) {
+	if relative, ok, err := parseRelativeDate(expr); ok {
+		if err != nil {
+			return dateRef{}, err
+		}
+		return dateRef{relative: relative}, nil
+	}
+	fixed, err := time.ParseInLocation(format, expr, loc)
+	if err != nil {
+		return dateRef{}, err
+	}
+	return dateRef{fixed: fixed}, nil
+}
+
+// resolve returns the reference date at the current time of env
+func (r dateRef) resolve(env ruleEnv) time.Time {
+	if r.relative == nil {
+		return r.fixed
+	}
+	return r.relative.at(env.now())
+}
+
+// parseRelativeDate parses expr when it starts with a relative base.
+// ok is false when expr is not a relative expression at all.
+func parseRelativeDate(expr string) (rel *relativeDate, ok bool, err error) {
+	for _, base := range relativeBases {
+		rest, found := strings.CutPrefix(expr, base)
+		if !found || (rest != "" && rest[0] != '+' && rest[0] != '-') {
+			continue
+		}
+		rel = &relativeDate{base: base}
+		for rest != "" {
+			sign := 1
+			if rest[0] == '-' {
+				sign = -1
+			}
+			rest = rest[1:]
+			digits := 0
+			for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+				digits++
+			}
+			if digits == 0 || digits == len(rest) {
+				return nil, true, fmt.Errorf("invalid relative date %q: expected a number followed by a unit", expr)
+			}
+			n, err := strconv.Atoi(rest[:digits])
+			if err != nil {
+				return nil, true, fmt.Errorf("invalid relative date %q: %v", expr, err)
+			}
+			unit := rest[digits]
+			if !strings.ContainsRune("yMwdhms", rune(unit)) {
+				return nil, true, fmt.Errorf("invalid relative date %q: unknown unit %q", expr, unit)
+			}
+			rel.offsets = append(rel.offsets, dateOffset{n: sign * n, unit: unit})
+			rest = rest[digits+1:]
+		}
+		return rel, true, nil
+	}
+	return nil, false, nil
+}
+
+// at evaluates the expression relative to now
+func (r *relativeDate) at(now time.Time) time.Time {
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+
+	var t time.Time
+	switch r.base {
+	case "now":
+		t = now
+	case "today":
+		t = today
+	case "yesterday":
+		t = today.AddDate(0, 0, -1)
+	case "tomorrow":
+		t = today.AddDate(0, 0, 1)
+	case "startOfWeek":
+		t = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	case "startOfMonth":
+		t = time.Date(y, m, 1, 0, 0, 0, 0, now.Location())
+	case "startOfYear":
+		t = time.Date(y, 1, 1, 0, 0, 0, 0, now.Location())
+	}
+
+	for _, off := range r.offsets {
+		switch off.unit {
+		case 'y':
+			t = t.AddDate(off.n, 0, 0)
+		case 'M':
+			t = t.AddDate(0, off.n, 0)
+		case 'w':
+			t = t.AddDate(0, 0, 7*off.n)
+		case 'd':
+			t = t.AddDate(0, 0, off.n)
+		case 'h':
+			t = t.Add(time.Duration(off.n) * time.Hour)
+		case 'm':
+			t = t.Add(time.Duration(off.n) * time.Minute)
+		case 's':
+			t = t.Add(time.Duration(off.n) * time.Second)
+		}
+	}
+	return t
+}
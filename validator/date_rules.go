@@ -1,27 +1,23 @@
 package validator
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"time"
 )
 
-// dateRule validates if a string is a valid date based on a user-provided format
+// defaultDateFormat parses string values and reference dates when a rule is given no format
+const defaultDateFormat = time.RFC3339
+
+// dateRule validates if a value is a valid date; strings must match a user-provided format
 func dateRule(fieldName string, value interface{}, params ...string) error {
 	if len(params) < 1 {
 		return fmt.Errorf("date rule requires a format parameter (e.g., '2006-01-02')")
 	}
 	format := params[0] // User-provided date format
 
-	str, ok := value.(string)
-	if !ok {
-		return fmt.Errorf("%s must be a string representing a date", fieldName)
-	}
-
-	_, err := time.Parse(format, str)
-	if err != nil {
-		return fmt.Errorf("%s must match the format %s", fieldName, format)
-	}
-	return nil
+	_, _, err := resolveDate(fieldName, value, format, defaultRuleEnv.location)
+	return err
 }
 
 // dateFormatRule checks if a date matches a custom format
@@ -31,39 +27,63 @@ func dateFormatRule(fieldName string, value interface{}, params ...string) error
 	}
 	format := params[0]
 
-	str, ok := value.(string)
-	if !ok {
-		return fmt.Errorf("%s must be a string representing a date", fieldName)
-	}
-	_, err := time.Parse(format, str)
-	if err != nil {
-		return fmt.Errorf("%s must match the format %s", fieldName, format)
+	_, _, err := resolveDate(fieldName, value, format, defaultRuleEnv.location)
+	return err
+}
+
+// compileLayoutRule binds a rule whose only parameter is a date layout
+func compileLayoutRule(rule Rule) ruleCompiler {
+	return func(_ ruleEnv, params []string) (checkFunc, error) {
+		if err := expectParams(params, 1); err != nil {
+			return nil, err
+		}
+		format := params[0]
+		return valueCheck(func(fieldName string, value interface{}) error {
+			return rule(fieldName, value, format)
+		}), nil
 	}
-	return nil
 }
 
 // afterDateRule ensures a date is after a specific date
 func afterDateRule(fieldName string, value interface{}, params ...string) error {
-	if len(params) < 2 {
-		return fmt.Errorf("after rule requires a reference date and format (e.g., '2024-01-01,2006-01-02')")
+	if len(params) < 1 {
+		return fmt.Errorf("after rule requires a reference date and format (e.g., '2024-01-01 2006-01-02')")
 	}
-	refDateStr, format := params[0], params[1]
+	refDateStr, format := params[0], formatParam(params, 1)
 
-	str, ok := value.(string)
-	if !ok {
-		return fmt.Errorf("%s must be a string representing a date", fieldName)
+	parsedValue, ok, err := resolveDate(fieldName, value, format, defaultRuleEnv.location)
+	if err != nil || !ok {
+		return err
 	}
 
-	parsedValue, err := time.Parse(format, str)
+	refDate, err := parseDateRef(refDateStr, format, defaultRuleEnv.location)
 	if err != nil {
-		return fmt.Errorf("%s must match the format %s", fieldName, format)
+		return fmt.Errorf("invalid reference date for %s", fieldName)
 	}
+	return checkAfterDate(fieldName, parsedValue, refDate.resolve(defaultRuleEnv), refDateStr)
+}
 
-	refDate, err := time.Parse(format, refDateStr)
-	if err != nil {
-		return fmt.Errorf("invalid reference date for %s", fieldName)
+// compileAfterDate parses the reference date of the after rule once
+func compileAfterDate(env ruleEnv, params []string) (checkFunc, error) {
+	if err := expectParamRange(params, 1, 2); err != nil {
+		return nil, err
 	}
+	refDateStr, format := params[0], formatParam(params, 1)
+	refDate, err := parseDateRef(refDateStr, format, env.location)
+	if err != nil {
+		return nil, dateRefError("reference date", refDateStr, format, err)
+	}
+	return valueCheck(func(fieldName string, value interface{}) error {
+		parsedValue, ok, err := resolveDate(fieldName, value, format, env.location)
+		if err != nil || !ok {
+			return err
+		}
+		return checkAfterDate(fieldName, parsedValue, refDate.resolve(env), refDateStr)
+	}), nil
+}
 
+// checkAfterDate compares a parsed date with the reference date of the after rule
+func checkAfterDate(fieldName string, parsedValue, refDate time.Time, refDateStr string) error {
 	if !parsedValue.After(refDate) {
 		return fmt.Errorf("%s must be after %s", fieldName, refDateStr)
 	}
@@ -72,26 +92,44 @@ func afterDateRule(fieldName string, value interface{}, params ...string) error
 
 // beforeDateRule ensures a date is before a specific date
 func beforeDateRule(fieldName string, value interface{}, params ...string) error {
-	if len(params) < 2 {
-		return fmt.Errorf("before rule requires a reference date and format (e.g., '2024-01-01,2006-01-02')")
+	if len(params) < 1 {
+		return fmt.Errorf("before rule requires a reference date and format (e.g., '2024-01-01 2006-01-02')")
 	}
-	refDateStr, format := params[0], params[1]
+	refDateStr, format := params[0], formatParam(params, 1)
 
-	str, ok := value.(string)
-	if !ok {
-		return fmt.Errorf("%s must be a string representing a date", fieldName)
+	parsedValue, ok, err := resolveDate(fieldName, value, format, defaultRuleEnv.location)
+	if err != nil || !ok {
+		return err
 	}
 
-	parsedValue, err := time.Parse(format, str)
+	refDate, err := parseDateRef(refDateStr, format, defaultRuleEnv.location)
 	if err != nil {
-		return fmt.Errorf("%s must match the format %s", fieldName, format)
+		return fmt.Errorf("invalid reference date for %s", fieldName)
 	}
+	return checkBeforeDate(fieldName, parsedValue, refDate.resolve(defaultRuleEnv), refDateStr)
+}
 
-	refDate, err := time.Parse(format, refDateStr)
-	if err != nil {
-		return fmt.Errorf("invalid reference date for %s", fieldName)
+// compileBeforeDate parses the reference date of the before rule once
+func compileBeforeDate(env ruleEnv, params []string) (checkFunc, error) {
+	if err := expectParamRange(params, 1, 2); err != nil {
+		return nil, err
 	}
+	refDateStr, format := params[0], formatParam(params, 1)
+	refDate, err := parseDateRef(refDateStr, format, env.location)
+	if err != nil {
+		return nil, dateRefError("reference date", refDateStr, format, err)
+	}
+	return valueCheck(func(fieldName string, value interface{}) error {
+		parsedValue, ok, err := resolveDate(fieldName, value, format, env.location)
+		if err != nil || !ok {
+			return err
+		}
+		return checkBeforeDate(fieldName, parsedValue, refDate.resolve(env), refDateStr)
+	}), nil
+}
 
+// checkBeforeDate compares a parsed date with the reference date of the before rule
+func checkBeforeDate(fieldName string, parsedValue, refDate time.Time, refDateStr string) error {
 	if !parsedValue.Before(refDate) {
 		return fmt.Errorf("%s must be before %s", fieldName, refDateStr)
 	}
@@ -100,126 +138,265 @@ func beforeDateRule(fieldName string, value interface{}, params ...string) error
 
 // betweenDateRule ensures a date is within a range
 func betweenDateRule(fieldName string, value interface{}, params ...string) error {
-	if len(params) < 3 {
-		return fmt.Errorf("between rule requires a start date, end date, and format (e.g., '2024-01-01,2024-12-31,2006-01-02')")
+	if len(params) < 2 {
+		return fmt.Errorf("between rule requires a start date, end date, and format (e.g., '2024-01-01 2024-12-31 2006-01-02')")
 	}
-	startDateStr, endDateStr, format := params[0], params[1], params[2]
+	startDateStr, endDateStr, format := params[0], params[1], formatParam(params, 2)
 
-	str, ok := value.(string)
-	if !ok {
-		return fmt.Errorf("%s must be a string representing a date", fieldName)
+	parsedValue, ok, err := resolveDate(fieldName, value, format, defaultRuleEnv.location)
+	if err != nil || !ok {
+		return err
 	}
 
-	parsedValue, err := time.Parse(format, str)
+	startDate, err := parseDateRef(startDateStr, format, defaultRuleEnv.location)
 	if err != nil {
-		return fmt.Errorf("%s must match the format %s", fieldName, format)
+		return fmt.Errorf("invalid start date for %s", fieldName)
 	}
 
-	startDate, err := time.Parse(format, startDateStr)
+	endDate, err := parseDateRef(endDateStr, format, defaultRuleEnv.location)
 	if err != nil {
-		return fmt.Errorf("invalid start date for %s", fieldName)
+		return fmt.Errorf("invalid end date for %s", fieldName)
 	}
+	return checkBetweenDate(fieldName, parsedValue, startDate.resolve(defaultRuleEnv), endDate.resolve(defaultRuleEnv), startDateStr, endDateStr)
+}
 
-	endDate, err := time.Parse(format, endDateStr)
+// compileBetweenDate parses the range of the between rule once
+func compileBetweenDate(env ruleEnv, params []string) (checkFunc, error) {
+	if err := expectParamRange(params, 2, 3); err != nil {
+		return nil, err
+	}
+	startDateStr, endDateStr, format := params[0], params[1], formatParam(params, 2)
+	startDate, err := parseDateRef(startDateStr, format, env.location)
 	if err != nil {
-		return fmt.Errorf("invalid end date for %s", fieldName)
+		return nil, dateRefError("start date", startDateStr, format, err)
 	}
+	endDate, err := parseDateRef(endDateStr, format, env.location)
+	if err != nil {
+		return nil, dateRefError("end date", endDateStr, format, err)
+	}
+	return valueCheck(func(fieldName string, value interface{}) error {
+		parsedValue, ok, err := resolveDate(fieldName, value, format, env.location)
+		if err != nil || !ok {
+			return err
+		}
+		return checkBetweenDate(fieldName, parsedValue, startDate.resolve(env), endDate.resolve(env), startDateStr, endDateStr)
+	}), nil
+}
 
+// checkBetweenDate compares a parsed date with the range of the between rule
+func checkBetweenDate(fieldName string, parsedValue, startDate, endDate time.Time, startDateStr, endDateStr string) error {
 	if parsedValue.Before(startDate) || parsedValue.After(endDate) {
 		return fmt.Errorf("%s must be between %s and %s", fieldName, startDateStr, endDateStr)
 	}
 	return nil
 }
-func pastDateRule(fieldName string, value interface{}, params ...string) error {
-	if len(params) < 1 {
-		return fmt.Errorf("past rule requires a format parameter (e.g., '2006-01-02')")
+
+// dateRefError describes a reference date that is neither a relative expression nor in the format
+func dateRefError(what, ref, format string, err error) error {
+	if _, isParseErr := err.(*time.ParseError); isParseErr {
+		return fmt.Errorf("%s %q does not match the format %s", what, ref, format)
 	}
-	format := params[0]
+	return err
+}
 
-	str, ok := value.(string)
-	if !ok {
-		return fmt.Errorf("%s must be a string representing a date", fieldName)
+// formatParam returns the optional format parameter at index i, defaulting to RFC 3339
+func formatParam(params []string, i int) string {
+	if len(params) > i {
+		return params[i]
 	}
+	return defaultDateFormat
+}
 
-	parsedValue, err := time.Parse(format, str)
-	if err != nil {
-		return fmt.Errorf("%s must match the format %s", fieldName, format)
+// resolveDate extracts a date from a time.Time, a *time.Time, a driver.Valuer
+// wrapper such as sql.NullTime, or a string in the given format.
+// Strings without a zone are interpreted in loc. ok is false when the value is
+// absent (a zero time, a nil pointer or an invalid wrapper); absent dates are left
+// to required.
+func resolveDate(fieldName string, value interface{}, format string, loc *time.Location) (t time.Time, ok bool, err error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, !v.IsZero(), nil
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false, nil
+		}
+		return *v, !v.IsZero(), nil
+	case string:
+		parsedValue, err := time.ParseInLocation(format, v, loc)
+		if err != nil {
+			return time.Time{}, false, reasonf(reasonDateFormat, fieldName, "%s must match the format %s", fieldName, format)
+		}
+		return parsedValue, true, nil
+	case driver.Valuer:
+		if isNil(v) {
+			return time.Time{}, false, nil
+		}
+		inner, err := v.Value()
+		if err != nil {
+			return time.Time{}, false, reasonf(reasonDateInvalid, fieldName, "%s must be a valid date", fieldName)
+		}
+		if inner == nil {
+			return time.Time{}, false, nil
+		}
+		if _, isValuer := inner.(driver.Valuer); isValuer {
+			return time.Time{}, false, reasonf(reasonDateInvalid, fieldName, "%s must be a valid date", fieldName)
+		}
+		return resolveDate(fieldName, inner, format, loc)
+	default:
+		return time.Time{}, false, reasonf(reasonDateType, fieldName, "%s must be a string representing a date", fieldName)
 	}
+}
 
-	today := time.Now().Truncate(24 * time.Hour)
+// temporalRule compares a date with the current date, or with the current time
+// when its format includes a time of day
+type temporalRule struct {
+	name    string
+	accept  func(cmp int) bool
+	message string
+}
+
+var (
+	pastRule            = temporalRule{"past", func(cmp int) bool { return cmp < 0 }, "%s must be in the past"}
+	futureRule          = temporalRule{"future", func(cmp int) bool { return cmp > 0 }, "%s must be in the future"}
+	pastInclusiveRule   = temporalRule{"past-inclusive", func(cmp int) bool { return cmp <= 0 }, "%s must be in the past or today"}
+	futureInclusiveRule = temporalRule{"future-inclusive", func(cmp int) bool { return cmp >= 0 }, "%s must be in the future or today"}
+)
 
-	if !parsedValue.Before(today) {
-		return fmt.Errorf("%s must be in the past", fieldName)
+// check resolves value with format and compares it with the current time of env
+func (r temporalRule) check(fieldName string, value interface{}, format string, dateOnly bool, env ruleEnv) error {
+	parsedValue, ok, err := resolveDate(fieldName, value, format, env.location)
+	if err != nil || !ok {
+		return err
+	}
+	if !r.accept(compareToNow(parsedValue.In(env.location), env.now(), dateOnly)) {
+		return fmt.Errorf(r.message, fieldName)
 	}
 	return nil
 }
 
-func futureDateRule(fieldName string, value interface{}, params ...string) error {
-	if len(params) < 1 {
-		return fmt.Errorf("future rule requires a format parameter (e.g., '2006-01-02')")
-	}
-	format := params[0]
+// validate implements the Rule signature using the system clock and local time zone
+func (r temporalRule) validate(fieldName string, value interface{}, params ...string) error {
+	format := formatParam(params, 0)
+	return r.check(fieldName, value, format, !layoutHasClock(format), defaultRuleEnv)
+}
 
-	str, ok := value.(string)
-	if !ok {
-		return fmt.Errorf("%s must be a string representing a date", fieldName)
-	}
+// compile binds the format once and reads the current time from the clock of env.
+// Without a format, time.Time values are compared with the current instant.
+func (r temporalRule) compile(env ruleEnv, params []string) (checkFunc, error) {
+	if err := expectParamRange(params, 0, 1); err != nil {
+		return nil, err
+	}
+	format := formatParam(params, 0)
+	dateOnly := !layoutHasClock(format)
+	return valueCheck(func(fieldName string, value interface{}) error {
+		return r.check(fieldName, value, format, dateOnly, env)
+	}), nil
+}
 
-	parsedValue, err := time.Parse(format, str)
-	if err != nil {
-		return fmt.Errorf("%s must match the format %s", fieldName, format)
+// compareToNow compares a date with now, returning -1, 0 or +1.
+// Dates without a time of day are compared by calendar day, so "today" is the
+// current day in the configured time zone instead of the current UTC day.
+func compareToNow(t, now time.Time, dateOnly bool) int {
+	if !dateOnly {
+		return t.Compare(now)
 	}
+	ty, tm, td := t.Date()
+	ny, nm, nd := now.Date()
+	return time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC).Compare(time.Date(ny, nm, nd, 0, 0, 0, 0, time.UTC))
+}
 
-	today := time.Now().Truncate(24 * time.Hour)
+// layoutHasClock reports whether a date layout includes a time of day
+func layoutHasClock(layout string) bool {
+	midnight := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	afternoon := time.Date(2000, 1, 1, 13, 14, 15, 0, time.UTC)
+	return midnight.Format(layout) != afternoon.Format(layout)
+}
 
-	if !parsedValue.After(today) {
-		return fmt.Errorf("%s must be in the future", fieldName)
-	}
-	return nil
+// pastDateRule ensures a date is before today
+func pastDateRule(fieldName string, value interface{}, params ...string) error {
+	return pastRule.validate(fieldName, value, params...)
+}
+
+// futureDateRule ensures a date is after today
+func futureDateRule(fieldName string, value interface{}, params ...string) error {
+	return futureRule.validate(fieldName, value, params...)
 }
 
 // pastInclusiveDateRule ensures a date is in the past or today
 func pastInclusiveDateRule(fieldName string, value interface{}, params ...string) error {
-	if len(params) < 1 {
-		return fmt.Errorf("past-inclusive rule requires a format parameter (e.g., '2006-01-02')")
-	}
-	format := params[0]
+	return pastInclusiveRule.validate(fieldName, value, params...)
+}
 
-	str, ok := value.(string)
-	if !ok {
-		return fmt.Errorf("%s must be a string representing a date", fieldName)
-	}
+// futureInclusiveDateRule ensures a date is in the future or today
+func futureInclusiveDateRule(fieldName string, value interface{}, params ...string) error {
+	return futureInclusiveRule.validate(fieldName, value, params...)
+}
 
-	parsedValue, err := time.Parse(format, str)
+// minDurationRule ensures a time.Duration is at least the given duration
+func minDurationRule(fieldName string, value interface{}, params ...string) error {
+	if len(params) < 1 {
+		return fmt.Errorf("minDuration rule requires a duration parameter (e.g., '1h30m')")
+	}
+	minimum, err := time.ParseDuration(params[0])
 	if err != nil {
-		return fmt.Errorf("%s must match the format %s", fieldName, format)
+		return fmt.Errorf("invalid minDuration parameter for %s", fieldName)
 	}
+	return checkMinDuration(fieldName, value, minimum)
+}
 
-	if parsedValue.After(time.Now()) {
-		return fmt.Errorf("%s must be in the past or today", fieldName)
+// checkMinDuration compares a duration value with a parsed minimum
+func checkMinDuration(fieldName string, value interface{}, minimum time.Duration) error {
+	if d, ok := durationValue(value); ok && d < minimum {
+		return fmt.Errorf("%s must be at least %s", fieldName, minimum)
 	}
 	return nil
 }
 
-// futureInclusiveDateRule ensures a date is in the future or today
-func futureInclusiveDateRule(fieldName string, value interface{}, params ...string) error {
+// maxDurationRule ensures a time.Duration is at most the given duration
+func maxDurationRule(fieldName string, value interface{}, params ...string) error {
 	if len(params) < 1 {
-		return fmt.Errorf("future-inclusive rule requires a format parameter (e.g., '2006-01-02')")
+		return fmt.Errorf("maxDuration rule requires a duration parameter (e.g., '24h')")
 	}
-	format := params[0]
-
-	str, ok := value.(string)
-	if !ok {
-		return fmt.Errorf("%s must be a string representing a date", fieldName)
-	}
-
-	parsedValue, err := time.Parse(format, str)
+	maximum, err := time.ParseDuration(params[0])
 	if err != nil {
-		return fmt.Errorf("%s must match the format %s", fieldName, format)
+		return fmt.Errorf("invalid maxDuration parameter for %s", fieldName)
 	}
+	return checkMaxDuration(fieldName, value, maximum)
+}
 
-	if parsedValue.Before(time.Now().Truncate(24 * time.Hour)) {
-		return fmt.Errorf("%s must be in the future or today", fieldName)
+// checkMaxDuration compares a duration value with a parsed maximum
+func checkMaxDuration(fieldName string, value interface{}, maximum time.Duration) error {
+	if d, ok := durationValue(value); ok && d > maximum {
+		return fmt.Errorf("%s must be at most %s", fieldName, maximum)
 	}
 	return nil
 }
+
+// compileDurationRule parses the duration parameter of a rule once
+func compileDurationRule(check func(fieldName string, value interface{}, d time.Duration) error) ruleCompiler {
+	return func(_ ruleEnv, params []string) (checkFunc, error) {
+		if err := expectParams(params, 1); err != nil {
+			return nil, err
+		}
+		d, err := time.ParseDuration(params[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration parameter %q", params[0])
+		}
+		return valueCheck(func(fieldName string, value interface{}) error {
+			return check(fieldName, value, d)
+		}), nil
+	}
+}
+
+// durationValue extracts a time.Duration from a value or a non-nil pointer
+func durationValue(value interface{}) (time.Duration, bool) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, true
+	case *time.Duration:
+		if v != nil {
+			return *v, true
+		}
+	}
+	return 0, false
+}
@@ -2,17 +2,40 @@ package validator
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
+// Codes of errors that are not reported by a rule
+const (
+	CodeInvalidTag = "invalid_tag" // the tag of the field is malformed
+	CodeDive       = "dive"        // dive was applied to a value that is not a collection
+	CodeStruct     = "struct"      // struct-level validation without a more specific code
+	CodeOr         = "or"          // every alternative of "a|b" failed
+	CodeNot        = "not"         // the rule negated by not(...) passed
+
+	CodeInvalidInput = "invalid_input" // the value passed to Validate cannot be validated
+)
+
+// Redacted replaces the rejected value of fields marked with the redact modifier
+const Redacted = "[redacted]"
+
 // ValidationError represents a validation error for a specific field
 type ValidationError struct {
-	Field   string
-	Message string
+	Field     string      // path of the field, e.g. Address.ZipCode or Tags[3]
+	Message   string      // rendered default message
+	Code      string      // stable code of the failed rule, e.g. "required" or "min"
+	Namespace string      // path including the type name of the top-level struct, e.g. User.Address.ZipCode
+	JSONPath  string      // path using json tag names, e.g. $.address.zip_code
+	Params    []string    // parameters of the failed rule
+	Value     interface{} // rejected value, or Redacted
 }
 
 // Error implements the error interface for ValidationError
 func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
 	return fmt.Sprintf("%s: %s", e.Field, e.Message)
 }
 
@@ -32,3 +55,43 @@ func (errs ValidationErrors) Error() string {
 func (errs ValidationErrors) HasErrors() bool {
 	return len(errs) > 0
 }
+
+// ByField groups the errors by field path, keeping their order within each field
+func (errs ValidationErrors) ByField() map[string]ValidationErrors {
+	fields := make(map[string]ValidationErrors)
+	for _, err := range errs {
+		fields[err.Field] = append(fields[err.Field], err)
+	}
+	return fields
+}
+
+// WithCode returns the errors reported with one of the given codes
+func (errs ValidationErrors) WithCode(codes ...string) ValidationErrors {
+	var filtered ValidationErrors
+	for _, err := range errs {
+		for _, code := range codes {
+			if err.Code == code {
+				filtered = append(filtered, err)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// InvalidInputError is returned by Check for values that cannot be validated:
+// nil, nil pointers, and values that are neither structs nor collections of structs
+type InvalidInputError struct {
+	Type reflect.Type // type of the value, nil for an untyped nil
+}
+
+// Error implements the error interface for InvalidInputError
+func (e *InvalidInputError) Error() string {
+	switch {
+	case e.Type == nil:
+		return "validator: cannot validate nil"
+	case e.Type.Kind() == reflect.Ptr || e.Type.Kind() == reflect.Interface:
+		return fmt.Sprintf("validator: cannot validate a nil %s", e.Type)
+	}
+	return fmt.Sprintf("validator: cannot validate %s, expected a struct, a pointer to a struct or a collection of structs", e.Type)
+}
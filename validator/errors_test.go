@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testSignupAddress struct {
+	Lines []string `json:"lines" validate:"dive,minSize=2"`
+}
+
+type testSignupMeta struct {
+	Source string `validate:"required"`
+}
+
+type testSignup struct {
+	testSignupMeta
+	Username string            `json:"user_name" validate:"required,minSize=3"`
+	Password string            `json:"password" validate:"redact,minSize=8"`
+	Age      int               `json:"age,omitempty" validate:"min=18"`
+	Address  testSignupAddress `json:"address"`
+	Labels   map[string]string `json:"labels" validate:"dive,required"`
+}
+
+// ValidateWith reports a struct-level error through the JSON-aware report
+func (s testSignup) ValidateWith(r *Report) {
+	if s.Username == s.Password {
+		r.AddCode("Password", "same_as_username", "Password must differ from Username")
+	}
+}
+
+// Test the machine-readable fields of validation errors
+func TestStructuredErrors(t *testing.T) {
+	errs := Validate(testSignup{
+		Username: "jo",
+		Password: "jo",
+		Age:      17,
+		Address:  testSignupAddress{Lines: []string{"Main St", "1"}},
+		Labels:   map[string]string{"a b": ""},
+	})
+
+	expected := ValidationErrors{
+		{Field: "Source", Message: "Source is required", Code: "required", Namespace: "testSignup.Source", JSONPath: "$.Source", Value: ""},
+		{Field: "Username", Message: "Username must have at least 3 elements", Code: "minSize", Namespace: "testSignup.Username", JSONPath: "$.user_name", Params: []string{"3"}, Value: "jo"},
+		{Field: "Password", Message: "Password must have at least 8 elements", Code: "minSize", Namespace: "testSignup.Password", JSONPath: "$.password", Params: []string{"8"}, Value: Redacted},
+		{Field: "Age", Message: "Age must be at least 18", Code: "min", Namespace: "testSignup.Age", JSONPath: "$.age", Params: []string{"18"}, Value: 17},
+		{Field: "Address.Lines[1]", Message: "Address.Lines[1] must have at least 2 elements", Code: "minSize", Namespace: "testSignup.Address.Lines[1]", JSONPath: "$.address.lines[1]", Params: []string{"2"}, Value: "1"},
+		{Field: "Labels[a b]", Message: "Labels[a b] is required", Code: "required", Namespace: "testSignup.Labels[a b]", JSONPath: "$.labels['a b']", Value: ""},
+		{Field: "Password", Message: "Password must differ from Username", Code: "same_as_username", Namespace: "testSignup.Password", JSONPath: "$.password"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(errs[i], expected[i]) {
+			t.Errorf("expected %#v, got %#v", expected[i], errs[i])
+		}
+	}
+
+	byField := errs.ByField()
+	if len(byField) != 6 || len(byField["Password"]) != 2 {
+		t.Errorf("unexpected grouping %v", byField)
+	}
+	if minSize := errs.WithCode("minSize", "min"); len(minSize) != 4 {
+		t.Errorf("expected 4 size errors, got %v", minSize)
+	}
+}
@@ -0,0 +1,64 @@
+package validator
+
+// Path locates a field in code generated by govalidator-gen. It renders the
+// Field, Namespace and JSONPath of errors like the reflective engine does.
+type Path struct {
+	path fieldPath
+	top  string // type name of the top-level struct
+}
+
+// RootPath returns the path of a top-level struct of the named type
+func RootPath(typeName string) Path {
+	return Path{path: rootPath, top: typeName}
+}
+
+// Field returns the path of a struct field with its reported and JSON names
+func (p Path) Field(name, json string) Path {
+	return Path{path: p.path.field(name, json), top: p.top}
+}
+
+// Index returns the path of an element of a slice or array
+func (p Path) Index(i int) Path {
+	return Path{path: p.path.index(i), top: p.top}
+}
+
+// Key returns the path of a map entry
+func (p Path) Key(key string) Path {
+	return Path{path: p.path.key(key), top: p.top}
+}
+
+// String returns the path as reported in the Field of errors
+func (p Path) String() string {
+	return p.path.name
+}
+
+// Fail reports that the built-in rule code failed for value at p. The message is
+// the one the rule renders for value, so generated code reports the same errors
+// as Validate.
+func (errs *ValidationErrors) Fail(p Path, code string, value interface{}, params ...string) {
+	message := code + " failed"
+	if rule, ok := ValidationRules[code]; ok {
+		if err := rule(p.path.name, value, params...); err != nil {
+			message = err.Error()
+		}
+	}
+	*errs = append(*errs, ValidationError{
+		Field:     p.path.name,
+		Message:   message,
+		Code:      code,
+		Namespace: joinPath(p.top, p.path.name),
+		JSONPath:  p.path.json,
+		Params:    params,
+		Value:     value,
+	})
+}
+
+// IsEmail reports whether s passes the email rule
+func IsEmail(s string) bool {
+	return isValidEmail(s)
+}
+
+// IsE164 reports whether s passes the e164 rule
+func IsE164(s string) bool {
+	return e164Regex.MatchString(s)
+}
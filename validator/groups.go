@@ -0,0 +1,156 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// DefaultGroup is the group of fields without a groups modifier, and the group
+// Validate checks. Struct-level validation belongs to it as well.
+const DefaultGroup = "default"
+
+// defaultGroups is the group list of a plain Validate call
+var defaultGroups = []string{DefaultGroup}
+
+// ValidateGroups validates s with the default Validator, checking only the fields of the given groups
+func ValidateGroups(s interface{}, groups ...string) ValidationErrors {
+	return defaultValidator.ValidateGroups(s, groups...)
+}
+
+// RegisterGroupSequence registers a group sequence on the default Validator
+func RegisterGroupSequence(name string, groups ...string) error {
+	return defaultValidator.RegisterGroupSequence(name, groups...)
+}
+
+// ValidateGroups validates only the fields that belong to one of the given groups,
+// e.g. validate:"groups=create update,required". Fields without a groups modifier belong
+// to DefaultGroup, which is also used when no group is given. Group sequences are
+// validated one group at a time, stopping at the first group that produces errors.
+func (v *Validator) ValidateGroups(s interface{}, groups ...string) ValidationErrors {
+	return v.ValidateGroupsContext(context.Background(), s, groups...)
+}
+
+// ValidateGroupsContext is like ValidateGroups, passing ctx to context-aware rules.
+// Input that cannot be validated, such as a nil pointer, is reported as a single
+// error with the code CodeInvalidInput.
+func (v *Validator) ValidateGroupsContext(ctx context.Context, s interface{}, groups ...string) ValidationErrors {
+	val, err := inputValue(s)
+	if err != nil {
+		return ValidationErrors{{Message: err.Error(), Code: CodeInvalidInput, JSONPath: rootPath.json}}
+	}
+	return v.validateGroups(ctx, val, groups)
+}
+
+// validateGroups validates val, resolving group sequences
+func (v *Validator) validateGroups(ctx context.Context, val reflect.Value, groups []string) ValidationErrors {
+	if len(groups) == 0 {
+		return v.validate(ctx, val, defaultGroups)
+	}
+
+	v.mu.RLock()
+	var plain []string
+	var sequences [][]string
+	for _, group := range groups {
+		if sequence, ok := v.groupSequences[group]; ok {
+			sequences = append(sequences, sequence)
+		} else {
+			plain = append(plain, group)
+		}
+	}
+	v.mu.RUnlock()
+
+	var errs ValidationErrors
+	if len(plain) > 0 {
+		errs = v.validate(ctx, val, plain)
+	}
+	limits := v.errorLimits(ctx)
+	for _, sequence := range sequences {
+		for _, group := range sequence {
+			if limits.full(errs) {
+				return errs
+			}
+			if step := v.validate(ctx, val, []string{group}); step.HasErrors() {
+				errs = append(errs, step...)
+				break
+			}
+		}
+	}
+	return limits.truncate(errs)
+}
+
+// RegisterGroupSequence defines name as the ordered sequence of groups. Validating the
+// sequence validates each group in turn and stops at the first group producing errors,
+// so expensive checks can be skipped until the basic ones pass.
+func (v *Validator) RegisterGroupSequence(name string, groups ...string) error {
+	if !isRuleName(name) || name == "" {
+		return fmt.Errorf("invalid group name %q", name)
+	}
+	if name == DefaultGroup {
+		return fmt.Errorf("group %q cannot be redefined as a sequence", name)
+	}
+	if len(groups) == 0 {
+		return fmt.Errorf("group sequence %q must contain at least one group", name)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.groupSequences[name]; !ok {
+		for _, sequence := range v.groupSequences {
+			if slices.Contains(sequence, name) {
+				return fmt.Errorf("group %q is part of a sequence and cannot be a sequence itself", name)
+			}
+		}
+	}
+	for _, group := range groups {
+		if !isRuleName(group) || group == "" {
+			return fmt.Errorf("invalid group name %q", group)
+		}
+		if _, ok := v.groupSequences[group]; ok || group == name {
+			return fmt.Errorf("group sequence %q cannot contain the sequence %q", name, group)
+		}
+	}
+	v.groupSequences[name] = slices.Clone(groups)
+	return nil
+}
+
+// fieldGroups extracts the groups of a field from its "groups" modifiers.
+// It returns nil for fields of the default group.
+func fieldGroups(nodes []ruleNode) ([]string, error) {
+	var groups []string
+	afterDive := false
+	for _, node := range nodes {
+		switch node.name {
+		case "dive":
+			afterDive = true
+		case "groups":
+			if afterDive {
+				return nil, &tagPosError{offset: node.offset, msg: "groups must come before dive"}
+			}
+			if node.params == nil {
+				return nil, &tagPosError{offset: node.offset, msg: "groups requires at least one group name"}
+			}
+			for _, group := range node.params {
+				if !isRuleName(group) {
+					return nil, &tagPosError{offset: node.offset, msg: fmt.Sprintf("invalid group name %q (groups are separated by spaces)", group)}
+				}
+			}
+			groups = append(groups, node.params...)
+		}
+	}
+	return groups, nil
+}
+
+// inGroups reports whether a field of the given groups is validated in a walk of the requested groups
+func inGroups(fieldGroups, requested []string) bool {
+	if fieldGroups == nil {
+		return slices.Contains(requested, DefaultGroup)
+	}
+	for _, group := range fieldGroups {
+		if slices.Contains(requested, group) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+type testGroupAddress struct {
+	ZipCode string `validate:"groups=create,required"`
+}
+
+type testGroupUser struct {
+	ID       string `validate:"groups=update,required"`
+	Email    string `validate:"groups=default create update,required"`
+	Nickname string `validate:"minSize=3"`
+	Address  testGroupAddress
+}
+
+// Test that fields are only validated in their groups
+func TestValidateGroups(t *testing.T) {
+	user := testGroupUser{Nickname: "jo"}
+
+	assertErrors(t, Validate(user), []string{
+		"Email: Email is required",
+		"Nickname: Nickname must have at least 3 elements",
+	})
+	assertErrors(t, ValidateGroups(user, "create"), []string{
+		"Email: Email is required",
+		"Address.ZipCode: Address.ZipCode is required",
+	})
+	assertErrors(t, ValidateGroups(user, "update", DefaultGroup), []string{
+		"ID: ID is required",
+		"Email: Email is required",
+		"Nickname: Nickname must have at least 3 elements",
+	})
+	assertErrors(t, ValidateGroups(user, "unknown"), nil)
+}
+
+// Test that a group sequence stops at the first group with errors
+func TestGroupSequence(t *testing.T) {
+	v := New()
+	if err := v.RegisterGroupSequence("checkout", "create", "update"); err != nil {
+		t.Fatal(err)
+	}
+
+	user := testGroupUser{Email: "john@example.com"}
+	assertErrors(t, v.ValidateGroups(user, "checkout"), []string{
+		"Address.ZipCode: Address.ZipCode is required",
+	})
+	user.Address.ZipCode = "12345"
+	assertErrors(t, v.ValidateGroups(user, "checkout"), []string{
+		"ID: ID is required",
+	})
+
+	for _, tc := range []struct {
+		name   string
+		groups []string
+	}{
+		{DefaultGroup, []string{"create"}},
+		{"nested", []string{"checkout"}},
+		{"create", []string{"basic"}},
+		{"empty", nil},
+		{"bad name", []string{"create"}},
+	} {
+		if err := v.RegisterGroupSequence(tc.name, tc.groups...); err == nil {
+			t.Errorf("expected an error registering sequence %q", tc.name)
+		}
+	}
+}
+
+// Test malformed groups modifiers
+func TestGroupsTagErrors(t *testing.T) {
+	err := New().Compile(struct {
+		Tags []string `validate:"dive,groups=create,required"`
+	}{})
+	if err == nil || !strings.HasSuffix(err.Error(), "at offset 5: groups must come before dive") {
+		t.Errorf("expected a groups position error, got %v", err)
+	}
+	if err := RegisterRule("groups", evenRule); err == nil {
+		t.Error("expected groups to be a reserved rule name")
+	}
+}
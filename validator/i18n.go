@@ -0,0 +1,372 @@
+package validator
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template keys that are not rule codes. Rules failing for a reason other than their
+// own, e.g. "after" given a malformed date, are translated with the template of the reason.
+const (
+	reasonDateFormat   = "date_format"  // a string date does not match the layout
+	reasonDateInvalid  = "date_invalid" // a date wrapper cannot produce its value
+	reasonDateType     = "date_type"    // the value cannot hold a date
+	reasonIncomparable = "incomparable" // a cross-field comparison of unordered values
+
+	templateCondition = "_condition" // one field/value pair of a condition, e.g. "{field} is {value}"
+	templateAnd       = "_and"       // joins the pairs of a condition and the fields of *_all rules
+	templateOr        = "_or"        // joins alternatives and the fields of required_with/without
+)
+
+// reasonError is the error of a rule failing for the reason its template is keyed by.
+// field is the field the message is about, which is not always the validated one.
+type reasonError struct {
+	reason string
+	field  string
+	msg    string
+}
+
+func (e *reasonError) Error() string {
+	return e.msg
+}
+
+// reasonf formats the default message of a reasonError
+func reasonf(reason, field, format string, args ...interface{}) error {
+	return &reasonError{reason: reason, field: field, msg: fmt.Sprintf(format, args...)}
+}
+
+//go:embed locales/*.json
+var bundledLocales embed.FS
+
+// Translator renders validation messages from templates keyed by locale and rule code,
+// e.g. "{field} must be at least {min}". Templates may use {field}, {value}, the
+// positional parameters {0}, {1}, ... and the named parameters of built-in rules.
+// A Translator is safe for concurrent use.
+type Translator struct {
+	mu       sync.RWMutex
+	catalogs map[string]map[string]string
+}
+
+// DefaultTranslator is used by Validators created without WithTranslator.
+// Catalogs added to it are available to every such Validator.
+var DefaultTranslator = NewTranslator()
+
+// NewTranslator creates a Translator with the bundled "en", "pt" and "es" catalogs
+func NewTranslator() *Translator {
+	t := &Translator{catalogs: make(map[string]map[string]string)}
+	entries, err := bundledLocales.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		f, err := bundledLocales.Open("locales/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		err = t.LoadJSON(strings.TrimSuffix(entry.Name(), ".json"), f)
+		f.Close()
+		if err != nil {
+			panic(fmt.Sprintf("validator: bundled catalog %s: %v", entry.Name(), err))
+		}
+	}
+	return t
+}
+
+// Add merges templates into the catalog of locale, replacing templates with the same code
+func (t *Translator) Add(locale string, templates map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	catalog, ok := t.catalogs[locale]
+	if !ok {
+		catalog = make(map[string]string, len(templates))
+		t.catalogs[locale] = catalog
+	}
+	for code, template := range templates {
+		catalog[code] = template
+	}
+}
+
+// LoadJSON adds the templates of a JSON object mapping rule codes to templates
+func (t *Translator) LoadJSON(locale string, r io.Reader) error {
+	var templates map[string]string
+	if err := json.NewDecoder(r).Decode(&templates); err != nil {
+		return fmt.Errorf("loading %s catalog: %w", locale, err)
+	}
+	t.Add(locale, templates)
+	return nil
+}
+
+// LoadYAML adds the templates of a YAML mapping of rule codes to templates
+func (t *Translator) LoadYAML(locale string, r io.Reader) error {
+	var templates map[string]string
+	if err := yaml.NewDecoder(r).Decode(&templates); err != nil {
+		return fmt.Errorf("loading %s catalog: %w", locale, err)
+	}
+	t.Add(locale, templates)
+	return nil
+}
+
+// LoadFile adds the catalog of a .json, .yaml or .yml file. The locale is the
+// file name without its extension, e.g. "fr.yaml" or "pt-BR.json".
+func (t *Translator) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ext := filepath.Ext(path)
+	locale := strings.TrimSuffix(filepath.Base(path), ext)
+	switch strings.ToLower(ext) {
+	case ".json":
+		return t.LoadJSON(locale, f)
+	case ".yaml", ".yml":
+		return t.LoadYAML(locale, f)
+	default:
+		return fmt.Errorf("unsupported catalog format %q", ext)
+	}
+}
+
+// Locales returns the locales with a catalog, sorted
+func (t *Translator) Locales() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	locales := make([]string, 0, len(t.catalogs))
+	for locale := range t.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// template looks up the template of code, falling back from a regional locale
+// such as "pt-BR" to its language
+func (t *Translator) template(locale, code string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for {
+		if template, ok := t.catalogs[locale][code]; ok {
+			return template, true
+		}
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			return "", false
+		}
+		locale = locale[:i]
+	}
+}
+
+// WithTranslator makes the Validator render messages with t instead of DefaultTranslator
+func WithTranslator(t *Translator) Option {
+	return func(v *Validator) {
+		v.translator = t
+	}
+}
+
+// WithDefaultLocale sets the locale of calls whose context carries none.
+// Without it such calls keep the default English messages of the rules.
+func WithDefaultLocale(locale string) Option {
+	return func(v *Validator) {
+		v.locale = locale
+	}
+}
+
+// localeKey is the context key of the locale set by ContextWithLocale
+type localeKey struct{}
+
+// ContextWithLocale returns a copy of ctx selecting the locale of validation messages
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale set by ContextWithLocale, or ""
+func LocaleFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// paramNames names the parameters of built-in rules for templates
+var paramNames = map[string][]string{
+	"min":              {"min"},
+	"max":              {"max"},
+	"size":             {"size"},
+	"minSize":          {"min"},
+	"maxSize":          {"max"},
+	"date":             {"format"},
+	"date-format":      {"format"},
+	"after":            {"date", "format"},
+	"before":           {"date", "format"},
+	"between":          {"start", "end", "format"},
+	"past":             {"format"},
+	"future":           {"format"},
+	"pastInclusive":    {"format"},
+	"futureInclusive":  {"format"},
+	"past-inclusive":   {"format"},
+	"future-inclusive": {"format"},
+	"minDuration":      {"min"},
+	"maxDuration":      {"max"},
+	"eqfield":          {"other"},
+	"nefield":          {"other"},
+	"gtfield":          {"other"},
+	"gtefield":         {"other"},
+	"ltfield":          {"other"},
+	"ltefield":         {"other"},
+	"afterfield":       {"other", "format"},
+	"beforefield":      {"other", "format"},
+	CodeNot:            {"rule"},
+}
+
+// conditionCodes are the rules whose parameters are field/value pairs, rendered as {condition}
+var conditionCodes = map[string]bool{
+	"required_if":     true,
+	"required_unless": true,
+	"excluded_if":     true,
+	"excluded_unless": true,
+}
+
+// presenceSeparators are the rules whose parameters are field names, rendered as {fields}
+// joined by the template of the separator
+var presenceSeparators = map[string]string{
+	"required_with":        templateOr,
+	"required_with_all":    templateAnd,
+	"required_without":     templateOr,
+	"required_without_all": templateAnd,
+}
+
+// localize renders the message of a failed rule in the locale of the walk. parent is the
+// type of the struct holding the field, used to name referenced fields. The default
+// message of err is kept when no locale is selected or the catalog lacks a template.
+func (w *walker) localize(path string, parent reflect.Type, code string, params []string, value interface{}, err error) string {
+	if w.locale == "" || w.v.translator == nil {
+		return err.Error()
+	}
+	if message, ok := w.translate(path, parent, code, params, value, err); ok {
+		return message
+	}
+	return err.Error()
+}
+
+// translate renders a template for err, reporting false when the catalog lacks one
+func (w *walker) translate(path string, parent reflect.Type, code string, params []string, value interface{}, err error) (string, bool) {
+	t := w.v.translator
+	if alts, ok := err.(*alternativesError); ok {
+		sep := w.connector(templateOr)
+		messages := make([]string, len(alts.errs))
+		for i, altErr := range alts.errs {
+			message, ok := w.translate(path, parent, alts.alts[i].name, alts.alts[i].params, value, altErr)
+			if !ok {
+				message = altErr.Error()
+			}
+			messages[i] = message
+		}
+		return strings.Join(messages, sep), true
+	}
+
+	key := code
+	if reason, ok := err.(*reasonError); ok {
+		key, path = reason.reason, reason.field
+	}
+	template, ok := t.template(w.locale, key)
+	if !ok {
+		return "", false
+	}
+
+	return render(template, w.templateVars(path, parent, code, params, value)), true
+}
+
+// templateVars returns the placeholders available to the template of a failed rule
+func (w *walker) templateVars(path string, parent reflect.Type, code string, params []string, value interface{}) map[string]string {
+	vars := map[string]string{"field": path, "value": fmt.Sprint(value), "format": defaultDateFormat}
+	for i, param := range params {
+		vars[strconv.Itoa(i)] = param
+	}
+	for i, name := range paramNames[code] {
+		if i < len(params) {
+			vars[name] = params[i]
+		}
+	}
+	if _, ok := vars["other"]; ok {
+		vars["other"] = convertPath(parent, vars["other"], w.v.fieldName)
+	}
+	if conditionCodes[code] {
+		vars["condition"] = w.condition(parent, params)
+	}
+	if sep, ok := presenceSeparators[code]; ok {
+		names := make([]string, len(params))
+		for i, param := range params {
+			names[i] = convertPath(parent, param, w.v.fieldName)
+		}
+		vars["fields"] = strings.Join(names, w.connector(sep))
+	}
+	return vars
+}
+
+// defaultConnectors render conditions for locales whose catalog lacks them
+var defaultConnectors = map[string]string{
+	templateCondition: "{field} is {value}",
+	templateAnd:       " and ",
+	templateOr:        " or ",
+}
+
+// connector returns the template joining the parts of a message in the locale of the walk
+func (w *walker) connector(key string) string {
+	if w.v.translator != nil {
+		if template, ok := w.v.translator.template(w.locale, key); ok {
+			return template
+		}
+	}
+	return defaultConnectors[key]
+}
+
+// condition renders the field/value pairs of a conditional rule
+func (w *walker) condition(parent reflect.Type, params []string) string {
+	pair, sep := w.connector(templateCondition), w.connector(templateAnd)
+	parts := make([]string, 0, len(params)/2)
+	for i := 0; i+1 < len(params); i += 2 {
+		parts = append(parts, render(pair, map[string]string{
+			"field": convertPath(parent, params[i], w.v.fieldName),
+			"value": params[i+1],
+		}))
+	}
+	return strings.Join(parts, sep)
+}
+
+// render replaces the {name} placeholders of template; unknown placeholders are kept
+func render(template string, vars map[string]string) string {
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		sb.WriteString(template[:start])
+		if value, ok := vars[template[start+1:end]]; ok {
+			sb.WriteString(value)
+		} else {
+			sb.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+	sb.WriteString(template)
+	return sb.String()
+}
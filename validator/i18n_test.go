@@ -0,0 +1,146 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testLocalizedOrder struct {
+	Customer      string `validate:"required"`
+	Quantity      int    `validate:"min=1"`
+	Contact       string `validate:"email|e164"`
+	PaymentMethod string
+	CardNumber    string `validate:"required_if=PaymentMethod card"`
+	Password      string
+	Confirm       string `validate:"eqfield=Password"`
+	ShipDate      string `validate:"after=2024-01-01 2006-01-02"`
+	Coupon        string `validate:"omitempty,not(non-blank)"`
+}
+
+var testInvalidOrder = testLocalizedOrder{
+	Quantity:      0,
+	Contact:       "nowhere",
+	PaymentMethod: "card",
+	Password:      "a",
+	Confirm:       "b",
+	ShipDate:      "01/02/2024",
+	Coupon:        "X",
+}
+
+// Test rendering the messages of built-in rules in the bundled locales
+func TestTranslatedMessages(t *testing.T) {
+	ctx := ContextWithLocale(context.Background(), "pt-BR")
+	assertErrors(t, ValidateContext(ctx, testInvalidOrder), []string{
+		"Customer: Customer é obrigatório",
+		"Quantity: Quantity deve ser no mínimo 1",
+		"Contact: Contact não é um e-mail válido ou Contact não é um telefone válido no formato E.164",
+		"CardNumber: CardNumber é obrigatório quando PaymentMethod é card",
+		"Confirm: Confirm deve ser igual a Password",
+		"ShipDate: ShipDate deve estar no formato 2006-01-02",
+		"Coupon: Coupon não deve satisfazer non-blank",
+	})
+
+	spanish := New(WithDefaultLocale("es"), WithFieldNameFunc(JSONFieldName))
+	errs := spanish.Validate(testInvalidOrder)
+	if errs[0].Message != "Customer es obligatorio" || errs[0].Code != "required" {
+		t.Errorf("expected the default locale, got %+v", errs[0])
+	}
+
+	// The locale of the context takes precedence, and "" keeps the rule messages
+	errs = spanish.ValidateContext(ContextWithLocale(context.Background(), "en"), testInvalidOrder)
+	if errs[3].Message != "CardNumber is required when PaymentMethod is card" {
+		t.Errorf("expected the context locale, got %q", errs[3].Message)
+	}
+	if errs := Validate(testInvalidOrder); errs[1].Message != "Quantity must be at least 1" {
+		t.Errorf("expected the default message, got %q", errs[1].Message)
+	}
+}
+
+// Test falling back to the default message for codes without a template
+func TestTranslatorFallback(t *testing.T) {
+	type account struct {
+		Handle string `validate:"handle"`
+	}
+	v := New(WithDefaultLocale("pt"), WithRule("handle", func(fieldName string, value interface{}, params ...string) error {
+		if strings.HasPrefix(value.(string), "@") {
+			return nil
+		}
+		return fmt.Errorf("%s must start with @", fieldName)
+	}))
+	assertErrors(t, v.Validate(account{Handle: "bob"}), []string{"Handle: Handle must start with @"})
+
+	translator := NewTranslator()
+	translator.Add("pt", map[string]string{"handle": "{field} deve começar com @, recebido {value}"})
+	v = New(WithDefaultLocale("pt"), WithTranslator(translator), WithRule("handle", v.rules["handle"].rule))
+	assertErrors(t, v.Validate(account{Handle: "bob"}), []string{"Handle: Handle deve começar com @, recebido bob"})
+
+	if _, ok := DefaultTranslator.template("pt", "handle"); ok {
+		t.Error("expected the default translator to be left unchanged")
+	}
+	if _, ok := translator.template("fr", "required"); ok {
+		t.Error("expected no template for a locale without a catalog")
+	}
+}
+
+// Test loading catalogs from JSON and YAML files
+func TestTranslatorLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"fr.json": `{"required": "{field} est obligatoire", "min": "{field} doit être au moins {min}"}`,
+		"de.yaml": "required: \"{field} ist erforderlich\"\nmin: \"{field} muss mindestens {0} sein\"\n",
+	}
+	translator := NewTranslator()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := translator.LoadFile(path); err != nil {
+			t.Fatalf("LoadFile(%s): %v", name, err)
+		}
+	}
+	if got := strings.Join(translator.Locales(), " "); got != "de en es fr pt" {
+		t.Errorf("unexpected locales %q", got)
+	}
+
+	v := New(WithTranslator(translator))
+	order := testLocalizedOrder{Contact: "a@b.co", ShipDate: "2024-02-01"}
+	for locale, want := range map[string][]string{
+		"fr": {"Customer: Customer est obligatoire", "Quantity: Quantity doit être au moins 1"},
+		"de": {"Customer: Customer ist erforderlich", "Quantity: Quantity muss mindestens 1 sein"},
+	} {
+		assertErrors(t, v.ValidateContext(ContextWithLocale(context.Background(), locale), order), want)
+	}
+
+	bad := filepath.Join(dir, "it.txt")
+	if err := os.WriteFile(bad, []byte("required=x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := translator.LoadFile(bad); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+	if err := translator.LoadJSON("it", strings.NewReader("[1]")); err == nil {
+		t.Error("expected an error for a malformed catalog")
+	}
+}
+
+// Test that the bundled catalogs cover every built-in rule
+func TestBundledCatalogs(t *testing.T) {
+	codes := []string{CodeNot, reasonDateFormat, reasonDateInvalid, reasonDateType, reasonIncomparable, templateCondition, templateAnd, templateOr}
+	for name := range defaultValidator.rules {
+		if name != "date" && name != "date-format" {
+			codes = append(codes, name)
+		}
+	}
+	for _, locale := range []string{"en", "pt", "es"} {
+		for _, code := range codes {
+			if _, ok := DefaultTranslator.template(locale, code); !ok {
+				t.Errorf("%s catalog has no template for %s", locale, code)
+			}
+		}
+	}
+}
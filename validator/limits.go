@@ -0,0 +1,90 @@
+package validator
+
+import "context"
+
+// ErrorLimits bound the errors a validation collects, trading complete reports for speed
+// on hot paths and untrusted input. The zero value collects every error.
+type ErrorLimits struct {
+	// MaxErrors stops the validation once it has collected that many errors; 0 means no limit.
+	// A MaxErrors of 1 fails fast, at the first error.
+	MaxErrors int
+	// FirstErrorPerField stops applying the rules of a field, element or map key at its
+	// first error, so "required,email" only reports required for an empty string
+	FirstErrorPerField bool
+}
+
+// WithErrorLimits sets the limits of calls whose context carries none
+func WithErrorLimits(limits ErrorLimits) Option {
+	return func(v *Validator) {
+		v.limits = limits
+	}
+}
+
+// WithFailFast makes the Validator stop at the first error
+func WithFailFast() Option {
+	return WithMaxErrors(1)
+}
+
+// WithMaxErrors makes the Validator stop once it has collected n errors
+func WithMaxErrors(n int) Option {
+	return func(v *Validator) {
+		v.limits.MaxErrors = n
+	}
+}
+
+// WithFirstErrorPerField makes the Validator report at most one error per field, element or map key
+func WithFirstErrorPerField() Option {
+	return func(v *Validator) {
+		v.limits.FirstErrorPerField = true
+	}
+}
+
+// limitsKey is the context key of the limits set by ContextWithErrorLimits
+type limitsKey struct{}
+
+// ContextWithErrorLimits returns a copy of ctx setting the limits of a single call,
+// replacing those the Validator was created with
+func ContextWithErrorLimits(ctx context.Context, limits ErrorLimits) context.Context {
+	return context.WithValue(ctx, limitsKey{}, limits)
+}
+
+// ErrorLimitsFromContext returns the limits set by ContextWithErrorLimits, if any
+func ErrorLimitsFromContext(ctx context.Context) (ErrorLimits, bool) {
+	if ctx == nil {
+		return ErrorLimits{}, false
+	}
+	limits, ok := ctx.Value(limitsKey{}).(ErrorLimits)
+	return limits, ok
+}
+
+// errorLimits returns the limits of a call with ctx
+func (v *Validator) errorLimits(ctx context.Context) ErrorLimits {
+	if limits, ok := ErrorLimitsFromContext(ctx); ok {
+		return limits
+	}
+	return v.limits
+}
+
+// full reports whether errs holds as many errors as the limits allow
+func (l ErrorLimits) full(errs ValidationErrors) bool {
+	return l.MaxErrors > 0 && len(errs) >= l.MaxErrors
+}
+
+// truncate drops the errors beyond MaxErrors, which struct-level validations
+// reporting several errors at once can add
+func (l ErrorLimits) truncate(errs ValidationErrors) ValidationErrors {
+	if l.full(errs) {
+		return errs[:l.MaxErrors]
+	}
+	return errs
+}
+
+// stops reports whether the remaining rules of the value at path are skipped: the call
+// has collected all the errors it may, or the value already failed a rule. The errors of
+// a value are reported together, so it failed if it holds the last error.
+func (w *walker) stops(errs ValidationErrors, path fieldPath) bool {
+	if w.limits.full(errs) {
+		return true
+	}
+	return w.limits.FirstErrorPerField && len(errs) > 0 && errs[len(errs)-1].Field == path.name
+}
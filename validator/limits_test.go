@@ -0,0 +1,114 @@
+package validator
+
+import (
+	"context"
+	"testing"
+)
+
+type testLimitsAddress struct {
+	Street string `validate:"required"`
+}
+
+type testLimitsUser struct {
+	Email   string   `validate:"required,email"`
+	Age     int      `validate:"min=18,max=10"`
+	Tags    []string `validate:"maxSize=1,dive,required,minSize=2"`
+	Address testLimitsAddress
+}
+
+// ValidateWith reports two errors at once, which limits must still bound
+func (u testLimitsUser) ValidateWith(r *Report) {
+	r.Add("Email", "must be unique")
+	r.Add("Age", "must be checked")
+}
+
+type testLimitsReport struct {
+	Name string
+}
+
+// ValidateWith reports two errors at once
+func (testLimitsReport) ValidateWith(r *Report) {
+	r.Add("Name", "must be unique")
+	r.Add("Name", "must be checked")
+}
+
+var testLimitsValue = testLimitsUser{Age: 15, Tags: []string{"", "go"}}
+
+// Test that all errors are collected by default
+func TestErrorLimitsNone(t *testing.T) {
+	assertErrors(t, New().Validate(testLimitsValue), []string{
+		"Email: Email is required",
+		"Email: Email is not a valid email",
+		"Age: Age must be at least 18",
+		"Age: Age must be at most 10",
+		"Tags: Tags must have at most 1 elements",
+		"Tags[0]: Tags[0] is required",
+		"Tags[0]: Tags[0] must have at least 2 elements",
+		"Address.Street: Address.Street is required",
+		"Email: must be unique",
+		"Age: must be checked",
+	})
+}
+
+// Test stopping at the first error, at a maximum and at the first error of each value
+func TestErrorLimits(t *testing.T) {
+	assertErrors(t, New(WithFailFast()).Validate(testLimitsValue), []string{
+		"Email: Email is required",
+	})
+	assertErrors(t, New(WithMaxErrors(6)).Validate(testLimitsValue), []string{
+		"Email: Email is required",
+		"Email: Email is not a valid email",
+		"Age: Age must be at least 18",
+		"Age: Age must be at most 10",
+		"Tags: Tags must have at most 1 elements",
+		"Tags[0]: Tags[0] is required",
+	})
+	assertErrors(t, New(WithFirstErrorPerField()).Validate(testLimitsValue), []string{
+		"Email: Email is required",
+		"Age: Age must be at least 18",
+		"Tags: Tags must have at most 1 elements",
+		"Tags[0]: Tags[0] is required",
+		"Address.Street: Address.Street is required",
+		"Email: must be unique",
+		"Age: must be checked",
+	})
+	assertErrors(t, New(WithFirstErrorPerField(), WithMaxErrors(3)).Validate(testLimitsValue), []string{
+		"Email: Email is required",
+		"Age: Age must be at least 18",
+		"Tags: Tags must have at most 1 elements",
+	})
+
+	// Struct-level validations reporting several errors are cut at the maximum
+	assertErrors(t, New(WithMaxErrors(1)).Validate(testLimitsReport{}), []string{"Name: must be unique"})
+}
+
+// Test that the limits of a call replace those of the Validator
+func TestErrorLimitsPerCall(t *testing.T) {
+	v := New(WithFailFast())
+	ctx := ContextWithErrorLimits(context.Background(), ErrorLimits{MaxErrors: 2})
+	assertErrors(t, v.ValidateContext(ctx, testLimitsValue), []string{
+		"Email: Email is required",
+		"Email: Email is not a valid email",
+	})
+	assertErrors(t, v.ValidateContext(ContextWithErrorLimits(ctx, ErrorLimits{}), testLimitsValue)[:3], []string{
+		"Email: Email is required",
+		"Email: Email is not a valid email",
+		"Age: Age must be at least 18",
+	})
+	if limits, ok := ErrorLimitsFromContext(ctx); !ok || limits.MaxErrors != 2 {
+		t.Errorf("expected the limits of the context, got %+v", limits)
+	}
+	if _, ok := ErrorLimitsFromContext(context.Background()); ok {
+		t.Error("expected no limits in a bare context")
+	}
+
+	// Collections stop at the first element with errors too
+	users := []testLimitsAddress{{Street: "Main"}, {}, {}}
+	assertErrors(t, v.Validate(users), []string{"[1].Street: [1].Street is required"})
+
+	// Group sequences stop once the maximum is reached
+	if err := v.RegisterGroupSequence("steps", DefaultGroup, "create"); err != nil {
+		t.Fatal(err)
+	}
+	assertErrors(t, v.ValidateGroups(testGroupUser{}, "steps"), []string{"Email: Email is required"})
+}
@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// alternativesError holds the error of every alternative of a failed "a|b"
+type alternativesError struct {
+	alts []boundRule
+	errs []error
+}
+
+// Error joins the messages of the alternatives by "or"
+func (e *alternativesError) Error() string {
+	messages := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, " or ")
+}
+
+// anyOf passes when at least one alternative passes, and otherwise reports
+// the errors of every alternative
+func anyOf(alts []boundRule) checkFunc {
+	return func(fc *FieldContext) error {
+		rule, params := fc.Rule, fc.Params
+		defer func() { fc.Rule, fc.Params = rule, params }()
+
+		errs := make([]error, 0, len(alts))
+		for _, alt := range alts {
+			fc.Rule, fc.Params = alt.name, alt.params
+			err := alt.check(fc)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		return &alternativesError{alts: alts, errs: errs}
+	}
+}
+
+// negate passes when operand fails. text is the operand as written in the tag.
+func negate(operand boundRule, text string) checkFunc {
+	return func(fc *FieldContext) error {
+		rule, params := fc.Rule, fc.Params
+		fc.Rule, fc.Params = operand.name, operand.params
+		err := operand.check(fc)
+		fc.Rule, fc.Params = rule, params
+		if err != nil {
+			return nil
+		}
+		return fmt.Errorf("%s must not satisfy %s", fc.Path, text)
+	}
+}
@@ -0,0 +1,53 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testLogic struct {
+	Password string
+	Contact  string `validate:"required,email|e164"`
+	Username string `validate:"not(eqfield=Password)"`
+	Code     string `validate:"not(email|e164),minSize=3|isEven"`
+}
+
+// Test alternatives and negations in tags
+func TestLogicalRules(t *testing.T) {
+	v := New()
+	err := v.RegisterRule("isEven", func(fieldName string, value interface{}, params ...string) error {
+		if len(params) != 0 {
+			t.Errorf("expected no parameters inside alternatives, got %v", params)
+		}
+		if s, _ := value.(string); len(s)%2 != 0 {
+			return fmt.Errorf("%s must have an even length", fieldName)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertErrors(t, v.Validate(testLogic{Password: "secret", Contact: "+5511987654321", Username: "john", Code: "AB"}), nil)
+	assertErrors(t, v.Validate(testLogic{Password: "secret", Contact: "john@example.com", Username: "john", Code: "ABC"}), nil)
+
+	assertErrors(t, v.Validate(testLogic{Password: "secret", Contact: "john", Username: "secret", Code: "+5511987654321"}), []string{
+		"Contact: Contact is not a valid email or Contact is not a valid E.164 phone number",
+		"Username: Username must not satisfy eqfield=Password",
+		"Code: Code must not satisfy email|e164",
+	})
+	assertErrors(t, v.Validate(testLogic{Password: "secret", Contact: "+12", Username: "john", Code: "A"}), []string{
+		"Code: Code must have at least 3 elements or Code must have an even length",
+	})
+
+	err = v.Compile(struct {
+		Tags []string `validate:"dive|required"`
+	}{})
+	if err == nil || !strings.HasSuffix(err.Error(), "at offset 0: dive cannot be used inside alternatives or not()") {
+		t.Errorf("expected a modifier error, got %v", err)
+	}
+	if err := v.RegisterRule("a|b", evenRule); err == nil {
+		t.Error("expected an invalid rule name error")
+	}
+}
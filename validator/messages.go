@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+)
+
+// messageTagName is the struct tag holding custom messages by rule code,
+// e.g. msg:"required=Please enter your name,min='At least {min}, please'"
+const messageTagName = "msg"
+
+// setMessages parses a msg tag and gives its messages to the rules of the plan
+// that have none from a msg modifier, including the rules of "when" and dives
+func (p *rulePlan) setMessages(tag string) error {
+	nodes, err := parseTag(tag)
+	if err != nil {
+		return err
+	}
+	messages := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		if node.args != nil || node.params == nil {
+			return &tagPosError{offset: node.offset, msg: "expected rule=message"}
+		}
+		messages[node.name] = strings.Join(node.params, " ")
+	}
+	p.applyMessages(messages)
+	return nil
+}
+
+// applyMessages sets the message of every rule of the plan with an entry in messages
+func (p *rulePlan) applyMessages(messages map[string]string) {
+	if p == nil {
+		return
+	}
+	for i := range p.rules {
+		if message, ok := messages[p.rules[i].name]; ok && p.rules[i].message == "" {
+			p.rules[i].message = message
+		}
+	}
+	if p.when != nil {
+		p.when.rules.applyMessages(messages)
+	}
+	if p.dive != nil {
+		p.dive.keys.applyMessages(messages)
+		p.dive.elem.applyMessages(messages)
+	}
+}
+
+// message renders the message of a failed rule: its custom message if it has one,
+// otherwise the message of the locale of the walk or the rule's own
+func (w *walker) message(path string, parent reflect.Type, rule boundRule, value interface{}, err error) string {
+	if rule.message != "" {
+		return render(rule.message, w.templateVars(path, parent, rule.name, rule.params, value))
+	}
+	return w.localize(path, parent, rule.name, rule.params, value, err)
+}
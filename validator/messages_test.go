@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type testMessageSignup struct {
+	Name     string   `validate:"required,msg=Please enter your name"`
+	Username string   `validate:"required,minSize=3" msg:"minSize='{field} needs {min}+ characters, got {value}'"`
+	Email    string   `validate:"required,email|e164" msg:"required=We need a way to reach you,or=Enter an e-mail or phone"`
+	Password string   `validate:"redact,minSize=8,msg=Password is too short: {value}"`
+	Tags     []string `validate:"dive,minSize=2" msg:"minSize=Tag {field} is too short"`
+	Age      int      `validate:"min=18"`
+}
+
+// Test overriding messages with the msg modifier and the msg tag
+func TestCustomMessages(t *testing.T) {
+	signup := testMessageSignup{Username: "ab", Email: "nowhere", Password: "secret", Tags: []string{"go", "x"}}
+	assertErrors(t, Validate(signup), []string{
+		"Name: Please enter your name",
+		"Username: Username needs 3+ characters, got ab",
+		"Email: Enter an e-mail or phone",
+		"Password: Password is too short: [redacted]",
+		"Tags[1]: Tag Tags[1] is too short",
+		"Age: Age must be at least 18",
+	})
+
+	// Custom messages take precedence over translations
+	errs := ValidateContext(ContextWithLocale(context.Background(), "pt"), testMessageSignup{Username: "abc", Password: "password"})
+	assertErrors(t, errs, []string{
+		"Name: Please enter your name",
+		"Email: We need a way to reach you",
+		"Email: Enter an e-mail or phone",
+		"Age: Age deve ser no mínimo 18",
+	})
+	if errs[1].Code != "required" {
+		t.Errorf("expected the code of the rule to be kept, got %q", errs[1].Code)
+	}
+}
+
+// Test reporting malformed custom messages as tag errors
+func TestCustomMessageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		s    interface{}
+		want string
+	}{
+		{"msg before any rule", struct {
+			Name string `validate:"msg=hello,required"`
+		}{}, "msg must follow the rule whose message it replaces"},
+		{"msg without message", struct {
+			Name string `validate:"required,msg"`
+		}{}, "msg requires a message"},
+		{"msg tag without message", struct {
+			Name string `validate:"required" msg:"required"`
+		}{}, "invalid msg tag"},
+		{"msg inside alternatives", struct {
+			Name string `validate:"email|msg=x"`
+		}{}, "msg cannot be used inside alternatives or not()"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Validate(tt.s)
+			if len(errs) != 1 || errs[0].Code != CodeInvalidTag || !strings.Contains(errs[0].Message, tt.want) {
+				t.Errorf("expected a tag error containing %q, got %v", tt.want, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldNameFunc returns the name a struct field is reported under in error paths and messages
+type FieldNameFunc func(field reflect.StructField) string
+
+// Field name resolvers for the common encoding tags. Fields without the tag,
+// or whose tag name is "-", keep their Go name.
+var (
+	JSONFieldName FieldNameFunc = TagFieldName("json")
+	YAMLFieldName FieldNameFunc = TagFieldName("yaml")
+	FormFieldName FieldNameFunc = TagFieldName("form")
+	XMLFieldName  FieldNameFunc = TagFieldName("xml")
+)
+
+// TagFieldName returns a FieldNameFunc reading the name from the given struct tag,
+// e.g. TagFieldName("json") reports `json:"user_name,omitempty"` as user_name
+func TagFieldName(key string) FieldNameFunc {
+	return func(field reflect.StructField) string {
+		name, _ := tagFieldName(field, key)
+		return name
+	}
+}
+
+// WithFieldNameFunc makes the Validator report fields under the names returned by fn,
+// e.g. validator.WithFieldNameFunc(validator.JSONFieldName) reports address.lines[2]
+// instead of Address.Lines[2]
+func WithFieldNameFunc(fn FieldNameFunc) Option {
+	return func(v *Validator) {
+		v.fieldName = fn
+	}
+}
+
+// tagFieldName returns the name a struct tag gives a field, and whether the tag names it
+func tagFieldName(field reflect.StructField, key string) (string, bool) {
+	name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+	if name == "" || name == "-" {
+		return field.Name, false
+	}
+	return name, true
+}
+
+// fieldName returns the name of a field under fn, defaulting to the Go name
+func (fn FieldNameFunc) fieldName(field reflect.StructField) string {
+	if fn == nil {
+		return field.Name
+	}
+	if name := fn(field); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// convertPath converts a Go path relative to a struct of type typ, such as
+// Items[0].Price, to the names given by fn. Unknown fields are kept as written.
+func convertPath(typ reflect.Type, path string, fn FieldNameFunc) string {
+	var sb strings.Builder
+	for _, segment := range strings.Split(path, ".") {
+		name, index, indexed := strings.Cut(segment, "[")
+		if name != "" {
+			typ = derefType(typ)
+			if field, ok := structField(typ, name); ok {
+				name = fn.fieldName(field)
+				typ = field.Type
+			} else {
+				typ = nil
+			}
+			if sb.Len() > 0 {
+				sb.WriteString(".")
+			}
+			sb.WriteString(name)
+		}
+		if indexed {
+			sb.WriteString("[" + index)
+			for range strings.Count(segment, "[") {
+				if typ = derefType(typ); typ != nil && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map) {
+					typ = typ.Elem()
+				} else {
+					typ = nil
+				}
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testNamedAddress struct {
+	Lines []string `json:"lines" yaml:"address_lines" validate:"dive,minSize=2"`
+}
+
+type testNamedUser struct {
+	Username        string           `json:"user_name,omitempty" validate:"required"`
+	Password        string           `json:"password"`
+	PasswordConfirm string           `json:"password_confirm" validate:"eqfield=Password"`
+	Address         testNamedAddress `json:"address"`
+	Internal        string           `json:"-" validate:"required"`
+}
+
+// ValidateWith reports a struct-level error using the Go field name
+func (u testNamedUser) ValidateWith(r *Report) {
+	if len(u.Address.Lines) > 2 {
+		r.Add("Address.Lines[2]", "too many lines")
+	}
+}
+
+// Test reporting fields under the names of their json or yaml tags
+func TestFieldNameFunc(t *testing.T) {
+	user := testNamedUser{
+		Password:        "secret",
+		PasswordConfirm: "secrets",
+		Address:         testNamedAddress{Lines: []string{"Main St", "1", "x"}},
+	}
+
+	assertErrors(t, New(WithFieldNameFunc(JSONFieldName)).Validate(user), []string{
+		"user_name: user_name is required",
+		"password_confirm: password_confirm must be equal to password",
+		"address.lines[1]: address.lines[1] must have at least 2 elements",
+		"address.lines[2]: address.lines[2] must have at least 2 elements",
+		"Internal: Internal is required",
+		"address.lines[2]: too many lines",
+	})
+
+	errs := New(WithFieldNameFunc(YAMLFieldName)).Validate(user)
+	if errs[2].Field != "Address.address_lines[1]" || errs[2].JSONPath != "$.address.lines[1]" {
+		t.Errorf("expected yaml names in the path and json names in the JSON path, got %+v", errs[2])
+	}
+
+	upper := New(WithFieldNameFunc(func(field reflect.StructField) string {
+		return strings.ToUpper(field.Name)
+	}))
+	if errs := upper.Validate(user); errs[0].Field != "USERNAME" || errs[0].Namespace != "testNamedUser.USERNAME" {
+		t.Errorf("expected a custom field name, got %+v", errs[0])
+	}
+}
@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// fieldPath locates a value both by Go field names and by JSON names
+type fieldPath struct {
+	name string // e.g. Address.Lines[2]
+	json string // e.g. $.address.lines[2]
+}
+
+// rootPath is the path of the top-level struct
+var rootPath = fieldPath{json: "$"}
+
+// field returns the path of a struct field
+func (p fieldPath) field(name, json string) fieldPath {
+	return fieldPath{name: joinPath(p.name, name), json: p.json + "." + json}
+}
+
+// index returns the path of an element of a slice or array
+func (p fieldPath) index(i int) fieldPath {
+	s := "[" + strconv.Itoa(i) + "]"
+	return fieldPath{name: p.name + s, json: p.json + s}
+}
+
+// key returns the path of a map entry
+func (p fieldPath) key(k string) fieldPath {
+	return fieldPath{name: p.name + "[" + k + "]", json: p.json + jsonKey(k)}
+}
+
+// jsonKey renders a map key as a JSON path member, quoting keys that are not identifiers
+func jsonKey(k string) string {
+	if k != "" && isRuleName(k) && !strings.Contains(k, "-") {
+		return "." + k
+	}
+	return "['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(k) + "']"
+}
+
+// jsonName returns the name encoding/json uses for a field, and whether the json tag names it
+func jsonName(field reflect.StructField) (string, bool) {
+	return tagFieldName(field, "json")
+}
+
+// derefType returns the type pointed to by pointer types
+func derefType(typ reflect.Type) reflect.Type {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// structField looks up an exported field by name, reporting false when typ is not a struct
+func structField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	field, ok := typ.FieldByName(name)
+	return field, ok && field.IsExported()
+}
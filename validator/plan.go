@@ -0,0 +1,578 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// checkFunc is a rule bound to its parameters
+type checkFunc func(fc *FieldContext) error
+
+// valueCheck adapts a check that only needs the field name and value
+func valueCheck(check func(fieldName string, value interface{}) error) checkFunc {
+	return func(fc *FieldContext) error {
+		return check(fc.Path, fc.Value)
+	}
+}
+
+// ruleCompiler validates and binds the parameters of a rule once, when a plan is compiled.
+// The environment carries the clock and time zone date rules depend on.
+type ruleCompiler func(env ruleEnv, params []string) (checkFunc, error)
+
+// ruleEnv is the configuration a rule is compiled against
+type ruleEnv struct {
+	clock      Clock
+	location   *time.Location
+	structType reflect.Type // struct holding the field, used to resolve other fields
+	fieldName  FieldNameFunc
+	// resolve replaces the lookup of other fields in structType when tags are checked
+	// without a struct, by CheckTag
+	resolve func(path string) error
+}
+
+// defaultRuleEnv is used when date rules are called outside of a Validator
+var defaultRuleEnv = ruleEnv{clock: SystemClock, location: time.Local}
+
+// ruleEntry is a registered rule, in one of its three forms
+type ruleEntry struct {
+	rule       Rule
+	fieldRule  FieldRule
+	compile    ruleCompiler
+	predefined bool // copied from ValidationRules by New
+}
+
+// structPlan is the precompiled validation of a struct type
+type structPlan struct {
+	gen    uint64
+	fields []fieldPlan
+	errs   []*TagError
+	retry  bool // not cached, as rules unknown to the default Validator may be added to ValidationRules
+
+	// struct-level validation run after the fields
+	method        structMethod
+	pointerMethod bool
+	validators    []StructValidator
+}
+
+// fieldPlan is the precompiled validation of a single struct field
+type fieldPlan struct {
+	index      int
+	name       string
+	jsonName   string
+	jsonNested bool // an embedded struct named by its json tag is not promoted in JSON
+	field      reflect.StructField
+	tag        string
+	embedded   bool
+	exported   bool
+	nested     bool
+	redact     bool     // rejected values are reported as Redacted
+	groups     []string // nil for the default group
+	rules      *rulePlan
+	err        *TagError
+}
+
+// rulePlan is a compiled tag: rules applied to the value itself, then either
+// a "when" guard holding the remaining rules or an optional dive
+type rulePlan struct {
+	omit  omitMode
+	rules []boundRule
+	when  *whenPlan
+	dive  *divePlan
+}
+
+// omitMode tells which values skip the rules of a plan
+type omitMode int
+
+const (
+	omitNone  omitMode = iota
+	omitEmpty          // "omitempty": nil, zero values and pointers to zero values
+	omitNil            // "omitnil": nil pointers, interfaces, slices and maps
+)
+
+// skips reports whether value is left unvalidated under the mode
+func (m omitMode) skips(value reflect.Value) bool {
+	switch m {
+	case omitNil:
+		return isNilValue(value)
+	case omitEmpty:
+		if value.Kind() == reflect.Ptr && !value.IsNil() {
+			value = value.Elem()
+		}
+		return !value.IsValid() || isEmpty(value.Interface())
+	}
+	return false
+}
+
+// isNilValue reports whether value is invalid or a nil pointer, interface, slice, map, func or chan
+func isNilValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+		return value.IsNil()
+	}
+	return false
+}
+
+// boundRule is a rule of a tag bound to its parameters
+type boundRule struct {
+	name    string
+	params  []string
+	check   checkFunc
+	message string // custom message template from msg, replacing the rule's own
+}
+
+// divePlan holds the rules applied to map keys and to the elements of a collection
+type divePlan struct {
+	keys *rulePlan
+	elem *rulePlan
+}
+
+// Compile compiles and caches the plans of s's type and of every struct type reachable from it.
+// It returns the tag errors found, so malformed tags can be detected at startup.
+func (v *Validator) Compile(s interface{}) error {
+	var errs []error
+	seen := map[reflect.Type]bool{}
+	var visit func(t reflect.Type)
+	visit = func(t reflect.Type) {
+		for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+			if t.Kind() == reflect.Map {
+				visit(t.Key())
+			}
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct || seen[t] {
+			return
+		}
+		seen[t] = true
+		plan := v.structPlan(t)
+		for _, err := range plan.errs {
+			errs = append(errs, err)
+		}
+		for i := 0; i < t.NumField(); i++ {
+			visit(t.Field(i).Type)
+		}
+	}
+	visit(reflect.TypeOf(s))
+	return errors.Join(errs...)
+}
+
+// CheckTag compiles a tag against the rules of v without a field to validate it for,
+// for tools such as linters. It reports the errors Validate would report for the tag,
+// and date layouts without any element of Go's reference time, such as "YYYY-MM-DD".
+// resolve checks the field paths referenced by cross-field and conditional rules,
+// which are all accepted when it is nil. Errors are *TagError values locating the problem.
+func (v *Validator) CheckTag(tag string, resolve func(path string) error) error {
+	if resolve == nil {
+		resolve = func(string) error { return nil }
+	}
+	err := v.checkTag(tag, resolve)
+	if err == nil {
+		return nil
+	}
+	var pos *tagPosError
+	errors.As(err, &pos)
+	return &TagError{Key: v.tagName, Tag: tag, Offset: pos.offset, Msg: pos.msg}
+}
+
+// checkTag compiles a tag like compileTag does, then checks its date layouts
+func (v *Validator) checkTag(tag string, resolve func(path string) error) error {
+	nodes, err := parseTag(tag)
+	if err != nil {
+		return err
+	}
+	if _, _, err = fieldModifiers(nodes); err != nil {
+		return err
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if _, err = v.compileRules(nodes, nil, ruleEnv{clock: v.clock, location: v.location, fieldName: v.fieldName, resolve: resolve}); err != nil {
+		return err
+	}
+	return v.checkLayouts(nodes)
+}
+
+// layoutParams is the position of the date layout among the parameters of date rules
+var layoutParams = map[string]int{
+	"date": 0, "date-format": 0, "after": 1, "before": 1, "between": 2,
+	"past": 0, "future": 0, "pastInclusive": 0, "futureInclusive": 0, "past-inclusive": 0, "future-inclusive": 0,
+	"afterfield": 1, "beforefield": 1,
+}
+
+// checkLayouts reports date layouts given to predefined date rules that hold no date or time
+// element, which Validate accepts but no date ever matches. The caller must hold v.mu.
+func (v *Validator) checkLayouts(nodes []ruleNode) error {
+	for _, node := range nodes {
+		if err := v.checkLayouts(node.args); err != nil {
+			return err
+		}
+		i, ok := layoutParams[node.name]
+		if !ok || i >= len(node.params) || v.rules[node.name].compile == nil {
+			continue
+		}
+		if layout := node.params[i]; !isDateLayout(layout) {
+			return &tagPosError{offset: node.offset, msg: fmt.Sprintf("invalid date layout %q: use the reference time, e.g. 2006-01-02", layout)}
+		}
+	}
+	return nil
+}
+
+// isDateLayout reports whether layout formats some element of a date, reading back what it formats
+func isDateLayout(layout string) bool {
+	sample := time.Date(2017, time.November, 28, 21, 37, 49, 123456789, time.UTC)
+	formatted := sample.Format(layout)
+	if formatted == layout {
+		return false
+	}
+	_, err := time.Parse(layout, formatted)
+	return err == nil
+}
+
+// mapRule looks up a rule of the default Validator in ValidationRules, which programs may add
+// rules to or replace predefined rules in. entry is the predefined rule, if any, whose
+// compiled form is kept while the map holds the same function.
+func mapRule(name string, entry ruleEntry, exists bool) (ruleEntry, bool) {
+	rule, ok := ValidationRules[name]
+	if !ok || rule == nil {
+		return entry, exists
+	}
+	if exists && entry.rule != nil && reflect.ValueOf(rule).Pointer() == reflect.ValueOf(entry.rule).Pointer() {
+		return entry, true
+	}
+	return ruleEntry{rule: rule}, true
+}
+
+// structPlan returns the cached plan for typ, compiling it on first use
+func (v *Validator) structPlan(typ reflect.Type) *structPlan {
+	gen := v.gen.Load()
+	if cached, ok := v.plans.Load(typ); ok {
+		if plan := cached.(*structPlan); plan.gen == gen {
+			return plan
+		}
+	}
+	plan := v.compileStruct(typ)
+	if !plan.retry {
+		v.plans.Store(typ, plan)
+	}
+	return plan
+}
+
+// compileStruct reads the tags of every field of typ and compiles their rules
+func (v *Validator) compileStruct(typ reflect.Type) *structPlan {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	plan := &structPlan{gen: v.gen.Load(), validators: slices.Concat(v.structValidators[typ], v.builderStructs[typ])}
+	plan.method, plan.pointerMethod = structMethodOf(typ)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get(v.tagName)
+		if declared, ok := v.fieldTags[typ][i]; ok {
+			tag = declared
+		}
+		if tag == "-" {
+			continue
+		}
+
+		fp := fieldPlan{
+			index:    i,
+			name:     v.fieldName.fieldName(field),
+			field:    field,
+			tag:      tag,
+			embedded: field.Anonymous && isStructType(field.Type),
+			exported: field.IsExported(),
+			nested:   isStructType(field.Type),
+		}
+		if !fp.exported && !fp.embedded {
+			continue
+		}
+		fp.jsonName, fp.jsonNested = jsonName(field)
+		if fp.exported && tag != "" {
+			key, text := v.tagName, tag
+			err := v.compileTag(&fp, typ)
+			if messages, ok := field.Tag.Lookup(messageTagName); ok && err == nil {
+				key, text = messageTagName, messages
+				err = fp.rules.setMessages(messages)
+			}
+			if err != nil {
+				var pos *tagPosError
+				errors.As(err, &pos)
+				fp.err = &TagError{
+					Key:    key,
+					Struct: typ.String(),
+					Field:  field.Name,
+					Tag:    text,
+					Offset: pos.offset,
+					Msg:    pos.msg,
+				}
+				plan.errs = append(plan.errs, fp.err)
+				plan.retry = plan.retry || (pos.unknown && v.fallback)
+			}
+		}
+		if fp.rules == nil && fp.err == nil && !fp.nested {
+			continue
+		}
+		plan.fields = append(plan.fields, fp)
+	}
+	return plan
+}
+
+// compileTag parses the tag of a field and compiles it against the type of the field
+// and the struct holding it, filling in the rules and modifiers of fp.
+// The caller must hold v.mu.
+func (v *Validator) compileTag(fp *fieldPlan, structType reflect.Type) error {
+	nodes, err := parseTag(fp.tag)
+	if err != nil {
+		return err
+	}
+	if fp.groups, fp.redact, err = fieldModifiers(nodes); err != nil {
+		return err
+	}
+	fp.rules, err = v.compileRules(nodes, fp.field.Type, ruleEnv{clock: v.clock, location: v.location, structType: structType, fieldName: v.fieldName})
+	return err
+}
+
+// fieldModifiers reads the modifiers that apply to a whole field: its groups and redact
+func fieldModifiers(nodes []ruleNode) (groups []string, redact bool, err error) {
+	if groups, err = fieldGroups(nodes); err != nil {
+		return nil, false, err
+	}
+	for _, node := range nodes {
+		if node.name == "redact" {
+			if node.params != nil {
+				return nil, false, &tagPosError{offset: node.offset, msg: "redact takes no parameters"}
+			}
+			redact = true
+		}
+	}
+	return groups, redact, nil
+}
+
+// compileRules resolves and binds a list of parsed rules, stopping at "dive".
+// The caller must hold v.mu.
+func (v *Validator) compileRules(nodes []ruleNode, typ reflect.Type, env ruleEnv) (*rulePlan, error) {
+	env, err := applyTimeZone(nodes, env)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &rulePlan{}
+	for i, node := range nodes {
+		switch node.name {
+		case "tz", "groups", "redact":
+			continue
+		case "msg":
+			if len(plan.rules) == 0 {
+				return nil, &tagPosError{offset: node.offset, msg: "msg must follow the rule whose message it replaces"}
+			}
+			if node.params == nil {
+				return nil, &tagPosError{offset: node.offset, msg: "msg requires a message"}
+			}
+			plan.rules[len(plan.rules)-1].message = strings.Join(node.params, " ")
+			continue
+		case "dive":
+			if node.params != nil {
+				return nil, &tagPosError{offset: node.offset, msg: "dive takes no parameters"}
+			}
+			dive, err := v.compileDive(node, nodes[i+1:], typ, env)
+			if err != nil {
+				return nil, err
+			}
+			plan.dive = dive
+			return plan, nil
+		case "keys", "endkeys":
+			return nil, &tagPosError{offset: node.offset, msg: fmt.Sprintf("%s must immediately follow dive on a map", node.name)}
+		case "omitempty", "omitnil":
+			if node.params != nil {
+				return nil, &tagPosError{offset: node.offset, msg: fmt.Sprintf("%s takes no parameters", node.name)}
+			}
+			if plan.omit != omitNone {
+				return nil, &tagPosError{offset: node.offset, msg: "only one of omitempty and omitnil can be used"}
+			}
+			plan.omit = omitEmpty
+			if node.name == "omitnil" {
+				plan.omit = omitNil
+			}
+			continue
+		case "when":
+			cond, err := compileWhen(env, node.params)
+			if err != nil {
+				return nil, &tagPosError{offset: node.offset, msg: fmt.Sprintf("when: %v", err)}
+			}
+			rest, err := v.compileRules(nodes[i+1:], typ, env)
+			if err != nil {
+				return nil, err
+			}
+			plan.when = &whenPlan{cond: cond, rules: rest}
+			return plan, nil
+		}
+
+		var next *ruleNode
+		if i+1 < len(nodes) {
+			next = &nodes[i+1]
+		}
+		rule, err := v.compileNode(node, next, env)
+		if err != nil {
+			return nil, err
+		}
+		plan.rules = append(plan.rules, rule)
+	}
+	return plan, nil
+}
+
+// compileNode binds a rule, or compiles the alternatives or negation it stands for.
+// next is the rule following it in the tag, if any. The caller must hold v.mu.
+func (v *Validator) compileNode(node ruleNode, next *ruleNode, env ruleEnv) (boundRule, error) {
+	switch node.name {
+	case orRule:
+		alts := make([]boundRule, len(node.args))
+		for i, arg := range node.args {
+			alt, err := v.compileNode(arg, nil, env)
+			if err != nil {
+				return boundRule{}, err
+			}
+			alts[i] = alt
+		}
+		return boundRule{name: CodeOr, check: anyOf(alts)}, nil
+	case notRule:
+		operand, err := v.compileNode(node.args[0], nil, env)
+		if err != nil {
+			return boundRule{}, err
+		}
+		return boundRule{name: CodeNot, params: []string{node.text}, check: negate(operand, node.text)}, nil
+	}
+	if isReservedRuleName(node.name) {
+		return boundRule{}, &tagPosError{offset: node.offset, msg: fmt.Sprintf("%s cannot be used inside alternatives or not()", node.name)}
+	}
+
+	// Rules written with the old comma-separated parameters get a hint
+	const commaHint = " (parameters are separated by spaces, not commas)"
+	entry, exists := v.rules[node.name]
+	if v.fallback && (!exists || entry.predefined) {
+		entry, exists = mapRule(node.name, entry, exists)
+	}
+	if !exists {
+		msg := fmt.Sprintf("unknown validation rule %q", node.name)
+		if !isRuleName(node.name) {
+			msg += commaHint
+		}
+		return boundRule{}, &tagPosError{offset: node.offset, msg: msg, unknown: true}
+	}
+	check, err := entry.bind(env, node.params)
+	if err != nil {
+		msg := fmt.Sprintf("%s: %v", node.name, err)
+		if next != nil && next.args == nil && !isRuleName(next.name) {
+			msg += commaHint
+		}
+		return boundRule{}, &tagPosError{offset: node.offset, msg: msg}
+	}
+	return boundRule{name: node.name, params: node.params, check: check}, nil
+}
+
+// compileDive compiles the rules following a "dive", splitting out the "keys" ... "endkeys" section.
+// The caller must hold v.mu.
+func (v *Validator) compileDive(node ruleNode, nodes []ruleNode, typ reflect.Type, env ruleEnv) (*divePlan, error) {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	var elemType reflect.Type
+	switch {
+	case typ == nil || typ.Kind() == reflect.Interface:
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map:
+		elemType = typ.Elem()
+	default:
+		return nil, &tagPosError{offset: node.offset, msg: fmt.Sprintf("dive can only be applied to slices, arrays and maps, got %s", typ)}
+	}
+
+	dive := &divePlan{}
+	if len(nodes) > 0 && nodes[0].name == "keys" {
+		if typ != nil && typ.Kind() != reflect.Map && typ.Kind() != reflect.Interface {
+			return nil, &tagPosError{offset: nodes[0].offset, msg: "keys can only be used when diving into a map"}
+		}
+		end := -1
+		for i, n := range nodes {
+			if n.name == "endkeys" {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return nil, &tagPosError{offset: nodes[0].offset, msg: "keys must be closed by endkeys"}
+		}
+		var keyType reflect.Type
+		if elemType != nil {
+			keyType = typ.Key()
+		}
+		keys, err := v.compileRules(nodes[1:end], keyType, env)
+		if err != nil {
+			return nil, err
+		}
+		dive.keys = keys
+		nodes = nodes[end+1:]
+	}
+	if len(nodes) > 0 {
+		elem, err := v.compileRules(nodes, elemType, env)
+		if err != nil {
+			return nil, err
+		}
+		dive.elem = elem
+	}
+	return dive, nil
+}
+
+// applyTimeZone returns env with the location of a "tz" modifier found before any dive
+func applyTimeZone(nodes []ruleNode, env ruleEnv) (ruleEnv, error) {
+	for _, node := range nodes {
+		if node.name == "dive" {
+			break
+		}
+		if node.name != "tz" {
+			continue
+		}
+		if len(node.params) != 1 {
+			return env, &tagPosError{offset: node.offset, msg: "tz requires exactly one time zone name"}
+		}
+		loc, err := time.LoadLocation(node.params[0])
+		if err != nil {
+			return env, &tagPosError{offset: node.offset, msg: fmt.Sprintf("tz: unknown time zone %q", node.params[0])}
+		}
+		env.location = loc
+	}
+	return env, nil
+}
+
+// bind returns the rule with its parameters applied, compiling them when the rule supports it.
+// Other rules read their parameters from FieldContext.Params at validation time.
+func (e ruleEntry) bind(env ruleEnv, params []string) (checkFunc, error) {
+	switch {
+	case e.compile != nil:
+		return e.compile(env, params)
+	case e.fieldRule != nil:
+		return checkFunc(e.fieldRule), nil
+	default:
+		return checkFunc(AdaptRule(e.rule)), nil
+	}
+}
+
+// isRuleName reports whether name looks like a rule name rather than a stray parameter
+func isRuleName(name string) bool {
+	for i, r := range name {
+		if !(unicode.IsLetter(r) || r == '_' || (i > 0 && (unicode.IsDigit(r) || r == '-'))) {
+			return false
+		}
+	}
+	return true
+}
+
+// isStructType reports whether t is a struct or a pointer to a struct
+func isStructType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
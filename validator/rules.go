@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -11,7 +12,10 @@ import (
 // Rule defines a validation function
 type Rule func(fieldName string, value interface{}, params ...string) error
 
-// Predefined validation rules
+// ValidationRules holds the predefined rules every Validator created by New starts with.
+// The package-level functions honor rules added to or replaced in the map, but Validators
+// created by New copy it and ignore later changes; prefer RegisterRule. A type is compiled
+// once, so replace rules before validating, and the map is not safe for concurrent use.
 var ValidationRules = map[string]Rule{
 	"required":       requiredRule,
 	"non-null":       nonNullRule,
@@ -20,6 +24,7 @@ var ValidationRules = map[string]Rule{
 	"min":            minRule,
 	"max":            maxRule,
 	"email":          emailRule,
+	"e164":           e164Rule,
 	"isTrue":         isTrueRule,
 	"positive":       positiveRule,
 	"negative":       negativeRule,
@@ -33,6 +38,117 @@ var ValidationRules = map[string]Rule{
 	"after":          afterDateRule,
 	"before":         beforeDateRule,
 	"between":        betweenDateRule,
+
+	"past":             pastDateRule,
+	"future":           futureDateRule,
+	"pastInclusive":    pastInclusiveDateRule,
+	"futureInclusive":  futureInclusiveDateRule,
+	"past-inclusive":   pastInclusiveDateRule,
+	"future-inclusive": futureInclusiveDateRule,
+	"minDuration":      minDurationRule,
+	"maxDuration":      maxDurationRule,
+}
+
+// ruleCompilers validate and bind the parameters of predefined rules once per plan
+var ruleCompilers = map[string]ruleCompiler{
+	"required":       compileNoParams(requiredRule),
+	"non-null":       compileNoParams(nonNullRule),
+	"non-blank":      compileNoParams(nonBlankRule),
+	"non-empty":      compileNoParams(nonEmptyRule),
+	"min":            compileIntRule(checkMin),
+	"max":            compileIntRule(checkMax),
+	"email":          compileNoParams(emailRule),
+	"e164":           compileNoParams(e164Rule),
+	"isTrue":         compileNoParams(isTrueRule),
+	"positive":       compileNoParams(positiveRule),
+	"negative":       compileNoParams(negativeRule),
+	"positiveOrZero": compileNoParams(positiveOrZeroRule),
+	"negativeOrZero": compileNoParams(negativeOrZeroRule),
+	"size":           compileIntRule(checkSize),
+	"minSize":        compileIntRule(checkMinSize),
+	"maxSize":        compileIntRule(checkMaxSize),
+	"date":           compileLayoutRule(dateRule),
+	"date-format":    compileLayoutRule(dateFormatRule),
+	"after":          compileAfterDate,
+	"before":         compileBeforeDate,
+	"between":        compileBetweenDate,
+
+	"past":             pastRule.compile,
+	"future":           futureRule.compile,
+	"pastInclusive":    pastInclusiveRule.compile,
+	"futureInclusive":  futureInclusiveRule.compile,
+	"past-inclusive":   pastInclusiveRule.compile,
+	"future-inclusive": futureInclusiveRule.compile,
+	"minDuration":      compileDurationRule(checkMinDuration),
+	"maxDuration":      compileDurationRule(checkMaxDuration),
+
+	// Cross-field rules only exist in compiled form, as they need the struct holding the field
+	"eqfield":     eqFieldRule.compile,
+	"nefield":     neFieldRule.compile,
+	"gtfield":     gtFieldRule.compile,
+	"gtefield":    gteFieldRule.compile,
+	"ltfield":     ltFieldRule.compile,
+	"ltefield":    lteFieldRule.compile,
+	"afterfield":  afterFieldRule.compile,
+	"beforefield": beforeFieldRule.compile,
+
+	// Conditional rules decide whether a field may be empty from its sibling fields
+	"required_if":          requiredIfRule.compile,
+	"required_unless":      requiredUnlessRule.compile,
+	"required_with":        requiredWithRule.compile,
+	"required_with_all":    requiredWithAllRule.compile,
+	"required_without":     requiredWithoutRule.compile,
+	"required_without_all": requiredWithoutAllRule.compile,
+	"excluded_if":          excludedIfRule.compile,
+	"excluded_unless":      excludedUnlessRule.compile,
+}
+
+// expectParams checks the number of parameters given to a rule
+func expectParams(params []string, n int) error {
+	return expectParamRange(params, n, n)
+}
+
+// expectParamRange checks that a rule was given between minimum and maximum parameters
+func expectParamRange(params []string, minimum, maximum int) error {
+	if len(params) >= minimum && len(params) <= maximum {
+		return nil
+	}
+	expected := strconv.Itoa(minimum)
+	if minimum != maximum {
+		expected += " to " + strconv.Itoa(maximum)
+	}
+	if len(params) > maximum {
+		return fmt.Errorf("expected %s parameter(s), got %d (quote parameters containing spaces)", expected, len(params))
+	}
+	return fmt.Errorf("expected %s parameter(s), got %d", expected, len(params))
+}
+
+// compileNoParams binds a rule that takes no parameters
+func compileNoParams(rule Rule) ruleCompiler {
+	return func(_ ruleEnv, params []string) (checkFunc, error) {
+		if err := expectParams(params, 0); err != nil {
+			return nil, err
+		}
+		return valueCheck(func(fieldName string, value interface{}) error {
+			return rule(fieldName, value)
+		}), nil
+	}
+}
+
+// compileIntRule parses the integer parameter of a rule once
+func compileIntRule(check func(fieldName string, value interface{}, n int) error) ruleCompiler {
+	return func(_ ruleEnv, params []string) (checkFunc, error) {
+		if err := expectParams(params, 1); err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(params[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer parameter %q", params[0])
+		}
+		return valueCheck(func(fieldName string, value interface{}) error {
+			return check(fieldName, value, n)
+		}), nil
+	}
 }
 
 // requiredRule checks if a value is not empty
@@ -76,6 +192,11 @@ func minRule(fieldName string, value interface{}, params ...string) error {
 	if err != nil {
 		return fmt.Errorf("invalid min parameter for %s", fieldName)
 	}
+	return checkMin(fieldName, value, minimum)
+}
+
+// checkMin compares an integer value with a parsed minimum
+func checkMin(fieldName string, value interface{}, minimum int) error {
 	if v, ok := value.(int); ok && v < minimum {
 		return fmt.Errorf("%s must be at least %d", fieldName, minimum)
 	}
@@ -91,6 +212,11 @@ func maxRule(fieldName string, value interface{}, params ...string) error {
 	if err != nil {
 		return fmt.Errorf("invalid max parameter for %s", fieldName)
 	}
+	return checkMax(fieldName, value, maximum)
+}
+
+// checkMax compares an integer value with a parsed maximum
+func checkMax(fieldName string, value interface{}, maximum int) error {
 	if v, ok := value.(int); ok && v > maximum {
 		return fmt.Errorf("%s must be at most %d", fieldName, maximum)
 	}
@@ -105,6 +231,14 @@ func emailRule(fieldName string, value interface{}, _ ...string) error {
 	return nil
 }
 
+// e164Rule checks if a string value is a phone number in E.164 format, e.g. +5511987654321
+func e164Rule(fieldName string, value interface{}, _ ...string) error {
+	if v, ok := value.(string); ok && !e164Regex.MatchString(v) {
+		return fmt.Errorf("%s is not a valid E.164 phone number", fieldName)
+	}
+	return nil
+}
+
 // Helper functions
 func isEmpty(value interface{}) bool {
 	if value == nil {
@@ -119,6 +253,15 @@ func isEmpty(value interface{}) bool {
 		return val.Len() == 0
 	case reflect.Ptr, reflect.Interface:
 		return val.IsNil()
+	case reflect.Struct:
+		// Wrappers such as sql.NullTime are empty when they hold no value
+		if valuer, ok := value.(driver.Valuer); ok {
+			if inner, err := valuer.Value(); err == nil && inner == nil {
+				return true
+			}
+		}
+		zero := reflect.Zero(val.Type()).Interface()
+		return reflect.DeepEqual(value, zero)
 	default:
 		zero := reflect.Zero(val.Type()).Interface()
 		return reflect.DeepEqual(value, zero)
@@ -133,10 +276,14 @@ func isNil(value interface{}) bool {
 	return val.Kind() == reflect.Ptr && val.IsNil()
 }
 
+// emailRegex is compiled once instead of on every call
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+// e164Regex matches a "+" followed by up to 15 digits, without a leading zero
+var e164Regex = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
 func isValidEmail(email string) bool {
-	const emailRegex = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
-	re := regexp.MustCompile(emailRegex)
-	return re.MatchString(email)
+	return emailRegex.MatchString(email)
 }
 
 // isTrueRule ensures a boolean value is `true`
@@ -188,7 +335,11 @@ func sizeRule(fieldName string, value interface{}, params ...string) error {
 	if err != nil {
 		return fmt.Errorf("invalid size parameter for %s", fieldName)
 	}
+	return checkSize(fieldName, value, size)
+}
 
+// checkSize compares the length of a collection with a parsed size
+func checkSize(fieldName string, value interface{}, size int) error {
 	if val, ok := getCollectionLength(value); ok && val == size {
 		return nil
 	}
@@ -204,7 +355,11 @@ func minSizeRule(fieldName string, value interface{}, params ...string) error {
 	if err != nil {
 		return fmt.Errorf("invalid minSize parameter for %s", fieldName)
 	}
+	return checkMinSize(fieldName, value, minSize)
+}
 
+// checkMinSize compares the length of a collection with a parsed minimum size
+func checkMinSize(fieldName string, value interface{}, minSize int) error {
 	if val, ok := getCollectionLength(value); ok && val >= minSize {
 		return nil
 	}
@@ -220,7 +375,11 @@ func maxSizeRule(fieldName string, value interface{}, params ...string) error {
 	if err != nil {
 		return fmt.Errorf("invalid maxSize parameter for %s", fieldName)
 	}
+	return checkMaxSize(fieldName, value, maxSize)
+}
 
+// checkMaxSize compares the length of a collection with a parsed maximum size
+func checkMaxSize(fieldName string, value interface{}, maxSize int) error {
 	if val, ok := getCollectionLength(value); ok && val <= maxSize {
 		return nil
 	}
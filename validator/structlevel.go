@@ -0,0 +1,261 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+)
+
+// Validatable is implemented by structs with invariants spanning several fields.
+// Validate calls the method after validating the fields of the struct; returned
+// ValidationErrors keep their fields, any other error is reported on the struct itself.
+// The method must not validate its own receiver with a Validator, which would recurse.
+// Embedded structs run their own method, which is skipped when their type is unexported.
+type Validatable interface {
+	Validate() error
+}
+
+// ReportValidatable is implemented by structs that report struct-level errors field by field
+type ReportValidatable interface {
+	ValidateWith(r *Report)
+}
+
+// StructValidator validates a whole struct of the type it is registered for,
+// reporting its errors through r. s is the struct value, never a pointer.
+type StructValidator func(r *Report, s interface{})
+
+var (
+	validatableType       = reflect.TypeOf((*Validatable)(nil)).Elem()
+	reportValidatableType = reflect.TypeOf((*ReportValidatable)(nil)).Elem()
+)
+
+// Report collects the errors of a struct-level validation.
+// Field names are relative to the struct being validated; "" means the struct itself.
+type Report struct {
+	w    *walker
+	path fieldPath    // path of the struct
+	typ  reflect.Type // type of the struct, to resolve JSON names
+	errs *ValidationErrors
+}
+
+// Context returns the context the validation was started with
+func (r *Report) Context() context.Context {
+	if r.w.ctx == nil {
+		return context.Background()
+	}
+	return r.w.ctx
+}
+
+// Add reports an error on a field of the struct
+func (r *Report) Add(field, message string) {
+	r.AddCode(field, CodeStruct, message)
+}
+
+// AddCode reports an error with a specific code on a field of the struct
+func (r *Report) AddCode(field, code, message string) {
+	r.report(field, code, nil, nil, message)
+}
+
+// Addf reports a formatted error on a field of the struct
+func (r *Report) Addf(field, format string, args ...interface{}) {
+	r.Add(field, fmt.Sprintf(format, args...))
+}
+
+// AddError reports err on a field of the struct. The fields and codes of ValidationErrors
+// and ValidationError are kept, relative to field; nil errors are ignored.
+func (r *Report) AddError(field string, err error) {
+	var list ValidationErrors
+	var single ValidationError
+	switch {
+	case err == nil:
+	case errors.As(err, &list):
+		for _, e := range list {
+			r.addValidationError(field, e)
+		}
+	case errors.As(err, &single):
+		r.addValidationError(field, single)
+	default:
+		r.Add(field, err.Error())
+	}
+}
+
+// addValidationError reports an error returned by a struct-level method
+func (r *Report) addValidationError(field string, e ValidationError) {
+	code := e.Code
+	if code == "" {
+		code = CodeStruct
+	}
+	r.report(joinPath(field, e.Field), code, e.Params, e.Value, e.Message)
+}
+
+// report appends an error on a field of the struct
+func (r *Report) report(field, code string, params []string, value interface{}, message string) {
+	path := r.fieldPath(field)
+	name := path.name
+	if field == "" && r.path.name == "" {
+		// Errors on the top-level struct itself are attributed to its type name
+		name = r.w.top.Type().Name()
+	}
+	message = r.w.localize(name, r.typ, code, params, value, errors.New(message))
+	r.w.report(r.errs, path, nil, code, params, value, message)
+	(*r.errs)[len(*r.errs)-1].Field = name
+}
+
+// fieldPath returns the full path of a field of the struct
+func (r *Report) fieldPath(field string) fieldPath {
+	switch {
+	case field == "":
+		return r.path
+	}
+	name, json := convertPath(r.typ, field, r.w.v.fieldName), convertPath(r.typ, field, JSONFieldName)
+	if field[0] != '[' {
+		json = "." + json
+		if r.path.name != "" {
+			name = "." + name
+		}
+	}
+	return fieldPath{name: r.path.name + name, json: r.path.json + json}
+}
+
+// structMethod is the struct-level validation method a type implements, if any
+type structMethod int
+
+const (
+	noStructMethod structMethod = iota
+	validateMethod
+	validateWithMethod
+)
+
+// name returns the name of the method
+func (m structMethod) name() string {
+	if m == validateWithMethod {
+		return "ValidateWith"
+	}
+	return "Validate"
+}
+
+// structMethodOf finds the struct-level validation method declared by typ. Methods promoted
+// from embedded structs are left out, as they run on the embedded structs themselves.
+// pointer reports whether the method needs a pointer receiver.
+func structMethodOf(typ reflect.Type) (method structMethod, pointer bool) {
+	for _, t := range []reflect.Type{typ, reflect.PointerTo(typ)} {
+		for _, candidate := range []struct {
+			method structMethod
+			iface  reflect.Type
+		}{{validateWithMethod, reportValidatableType}, {validateMethod, validatableType}} {
+			if !t.Implements(candidate.iface) {
+				continue
+			}
+			m, _ := t.MethodByName(candidate.method.name())
+			if declaredMethod(typ, m) {
+				return candidate.method, t != typ
+			}
+		}
+	}
+	return noStructMethod, false
+}
+
+// declaredMethod reports whether typ declares m rather than promoting it from an embedded
+// field. Go implements promoted methods with generated wrappers, which have no source file.
+func declaredMethod(typ reflect.Type, m reflect.Method) bool {
+	f := runtime.FuncForPC(m.Func.Pointer())
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		_, direct := field.Type.MethodByName(m.Name)
+		_, indirect := reflect.PointerTo(field.Type).MethodByName(m.Name)
+		if direct || indirect {
+			// An embedded field has the method too, so typ either shadows or promotes it
+			if file, _ := f.FileLine(f.Entry()); file == "<autogenerated>" {
+				return false
+			}
+			break
+		}
+	}
+	return true
+}
+
+// RegisterStructValidator registers a struct-level validator on the default Validator
+func RegisterStructValidator(s interface{}, fn StructValidator) error {
+	return defaultValidator.RegisterStructValidator(s, fn)
+}
+
+// WithStructValidator registers a struct-level validator for the type of s on the Validator
+func WithStructValidator(s interface{}, fn StructValidator) Option {
+	return func(v *Validator) {
+		if typ, ok := structTypeOf(s); ok && fn != nil {
+			v.structValidators[typ] = append(v.structValidators[typ], fn)
+		}
+	}
+}
+
+// RegisterStructValidator adds a validator run on every struct of the type of s,
+// which may be a struct value or a pointer to one, after its fields are validated.
+// Useful for types that cannot implement Validatable, such as generated code.
+func (v *Validator) RegisterStructValidator(s interface{}, fn StructValidator) error {
+	typ, ok := structTypeOf(s)
+	if !ok {
+		return fmt.Errorf("struct validators can only be registered for structs, got %T", s)
+	}
+	if fn == nil {
+		return fmt.Errorf("struct validator for %s must not be nil", typ)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.structValidators[typ] = append(v.structValidators[typ], fn)
+	v.invalidatePlans()
+	return nil
+}
+
+// structTypeOf returns the struct type of a struct value or pointer
+func structTypeOf(s interface{}) (reflect.Type, bool) {
+	typ := reflect.TypeOf(s)
+	if typ == nil {
+		return nil, false
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ, typ.Kind() == reflect.Struct
+}
+
+// validateStructLevel runs the struct-level validation method and registered validators of val
+// when the default group is validated. Embedded structs run their own methods, when they are set;
+// reflection cannot call the methods of embedded structs of unexported types, which are skipped.
+func (w *walker) validateStructLevel(val reflect.Value, prefix fieldPath, plan *structPlan, errs *ValidationErrors) {
+	if !slices.Contains(w.groups, DefaultGroup) {
+		return
+	}
+	method := plan.method
+	if (method == noStructMethod && len(plan.validators) == 0) || !val.CanInterface() {
+		return
+	}
+
+	r := &Report{w: w, path: prefix, typ: val.Type(), errs: errs}
+	if method != noStructMethod {
+		recv := val
+		if plan.pointerMethod {
+			if val.CanAddr() {
+				recv = val.Addr()
+			} else {
+				recv = reflect.New(val.Type())
+				recv.Elem().Set(val)
+			}
+		}
+		switch method {
+		case validateMethod:
+			r.AddError("", recv.Interface().(Validatable).Validate())
+		case validateWithMethod:
+			recv.Interface().(ReportValidatable).ValidateWith(r)
+		}
+	}
+	for _, fn := range plan.validators {
+		fn(r, val.Interface())
+	}
+}
@@ -0,0 +1,192 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+)
+
+type testContact struct {
+	Name  string `validate:"required"`
+	Email string
+	Phone string
+}
+
+// Validate requires at least one way to reach the contact
+func (c testContact) Validate() error {
+	if c.Email == "" && c.Phone == "" {
+		return errors.New("either Email or Phone must be set")
+	}
+	return nil
+}
+
+type testLine struct {
+	Amount int
+}
+
+type testOrder struct {
+	Lines   []testLine `validate:"dive"`
+	Total   int
+	Contact *testContact
+}
+
+// ValidateWith checks that the lines add up to the total
+func (o *testOrder) ValidateWith(r *Report) {
+	sum := 0
+	for _, line := range o.Lines {
+		sum += line.Amount
+	}
+	if sum != o.Total {
+		r.Addf("Total", "Total must equal the sum of the lines (%d)", sum)
+	}
+}
+
+// Test that struct-level methods run after field rules, with field attribution
+func TestStructLevelMethods(t *testing.T) {
+	order := testOrder{
+		Lines:   []testLine{{Amount: 2}, {Amount: 3}},
+		Total:   5,
+		Contact: &testContact{Name: "John", Phone: "123"},
+	}
+	assertErrors(t, Validate(order), nil)
+
+	order.Total = 6
+	order.Contact = &testContact{}
+	assertErrors(t, Validate(&order), []string{
+		"Contact.Name: Contact.Name is required",
+		"Contact: either Email or Phone must be set",
+		"Total: Total must equal the sum of the lines (5)",
+	})
+
+	assertErrors(t, Validate(testContact{Name: "John"}), []string{
+		"testContact: either Email or Phone must be set",
+	})
+}
+
+// BaseEntity is exported, as the methods of embedded structs of unexported types are skipped
+type BaseEntity struct {
+	ID string
+}
+
+// Validate runs on the BaseEntity embedded in other structs
+func (b BaseEntity) Validate() error {
+	if b.ID == "" {
+		return ValidationErrors{{Field: "ID", Message: "ID is required"}}
+	}
+	return nil
+}
+
+type testProduct struct {
+	BaseEntity
+	Name string
+}
+
+type Creator struct {
+	CreatedBy string
+}
+
+// Validate dereferences its receiver, so it must not run on a nil embedded pointer
+func (a *Creator) Validate() error {
+	if a.CreatedBy == "" {
+		return ValidationErrors{{Field: "CreatedBy", Message: "CreatedBy is required"}}
+	}
+	return nil
+}
+
+type testAuditedProduct struct {
+	*Creator
+	BaseEntity
+	Name string
+}
+
+// Validate shadows the methods of the embedded structs, which still run
+func (p testAuditedProduct) Validate() error {
+	if p.Name == "" {
+		return errors.New("a product needs a name")
+	}
+	return nil
+}
+
+type testTimestamps struct {
+	Created string
+}
+
+// Validate cannot run on an embedded testTimestamps, whose type is unexported
+func (s testTimestamps) Validate() error {
+	return errors.New("unreachable")
+}
+
+// Test that methods of embedded structs run once on each embedded struct that is set
+func TestStructLevelEmbedded(t *testing.T) {
+	assertErrors(t, Validate(struct {
+		testTimestamps
+		Name string
+	}{}), nil)
+
+	assertErrors(t, Validate(testProduct{Name: "Pen"}), []string{
+		"ID: ID is required",
+	})
+
+	// Both embedded structs declare Validate, which Go does not promote
+	assertErrors(t, Validate(struct {
+		*Creator
+		BaseEntity
+	}{Creator: &Creator{}}), []string{
+		"CreatedBy: CreatedBy is required",
+		"ID: ID is required",
+	})
+
+	assertErrors(t, Validate(testAuditedProduct{}), []string{
+		"ID: ID is required",
+		"testAuditedProduct: a product needs a name",
+	})
+	assertErrors(t, Validate(&testAuditedProduct{Creator: &Creator{}, Name: "Pen"}), []string{
+		"CreatedBy: CreatedBy is required",
+		"ID: ID is required",
+	})
+}
+
+// Test struct validators registered per type
+func TestRegisterStructValidator(t *testing.T) {
+	v := New()
+	err := v.RegisterStructValidator(&testLine{}, func(r *Report, s interface{}) {
+		if line := s.(testLine); line.Amount <= 0 {
+			r.Add("Amount", "Amount must be positive")
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.RegisterStructValidator(42, func(*Report, interface{}) {}); err == nil {
+		t.Error("expected an error registering a validator for a non-struct type")
+	}
+
+	order := testOrder{Lines: []testLine{{Amount: 1}, {Amount: 0}}, Total: 1}
+	assertErrors(t, v.Validate(order), []string{
+		"Lines[1].Amount: Amount must be positive",
+	})
+	// Other validators are not affected
+	assertErrors(t, New().Validate(order), nil)
+}
+
+type testLinkedAccount struct {
+	Bad    bool
+	linked *testLinkedAccount
+}
+
+// Validate validates the linked account, whose own Validate must still run
+func (a testLinkedAccount) Validate() error {
+	if a.Bad {
+		return errors.New("account bad")
+	}
+	if a.linked != nil {
+		return Validate(a.linked)
+	}
+	return nil
+}
+
+// Test that methods validating other values of their type are not skipped
+func TestStructLevelNestedValidate(t *testing.T) {
+	assertErrors(t, Validate(testLinkedAccount{linked: &testLinkedAccount{Bad: true}}), []string{
+		"testLinkedAccount: account bad",
+	})
+}
@@ -0,0 +1,276 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The grammar of a validate tag:
+//
+//	tag    = expr { "," expr }
+//	expr   = term { "|" term }
+//	term   = "not(" expr ")" | rule
+//	rule   = name [ "=" param { " " param } ]
+//	param  = bare | "'" quoted "'"
+//
+// Parameters are separated by spaces; quote them to include spaces, commas, "|" or ")",
+// e.g. after='2024-01-01 10:00' '2006-01-02 15:04'. A backslash escapes the
+// next character both inside and outside quotes.
+
+// Names of the nodes combining other rules
+const (
+	orRule  = "|"
+	notRule = "not"
+)
+
+// ruleNode is one parsed rule of a tag. Alternatives ("a|b") and negations
+// ("not(a)") hold their operands in args.
+type ruleNode struct {
+	name   string
+	params []string
+	offset int
+	args   []ruleNode
+	text   string // source of the operand of not(...), for messages
+}
+
+// TagError reports a tag that cannot be parsed or compiled
+type TagError struct {
+	Key    string // struct tag key, e.g. "validate"
+	Struct string
+	Field  string
+	Tag    string
+	Offset int // byte offset of the problem within Tag
+	Msg    string
+}
+
+// Error implements the error interface for TagError
+func (e *TagError) Error() string {
+	if e.Struct == "" {
+		return fmt.Sprintf("invalid %s tag at offset %d: %s", e.Key, e.Offset, e.Msg)
+	}
+	return fmt.Sprintf("invalid %s tag on %s.%s at offset %d: %s", e.Key, e.Struct, e.Field, e.Offset, e.Msg)
+}
+
+// tagPosError is a parse or compile error positioned within a tag
+type tagPosError struct {
+	offset  int
+	msg     string
+	unknown bool // the rule is unknown
+}
+
+func (e *tagPosError) Error() string {
+	return e.msg
+}
+
+// TagRule is a rule of a parsed tag. Alternatives ("a|b") are named "|" and negations
+// ("not(a)") "not"; both hold their operands in Args.
+type TagRule struct {
+	Name   string
+	Params []string
+	Offset int // byte offset of the rule within the tag
+	Args   []TagRule
+}
+
+// ParseTag parses a tag into its rules without compiling them, for tools such as
+// code generators and linters. Syntax errors are *TagError values locating the problem.
+func ParseTag(tag string) ([]TagRule, error) {
+	nodes, err := parseTag(tag)
+	if err != nil {
+		pos := err.(*tagPosError)
+		return nil, &TagError{Key: defaultTagName, Tag: tag, Offset: pos.offset, Msg: pos.msg}
+	}
+	return tagRules(nodes), nil
+}
+
+// tagRules converts parsed nodes to their exported form
+func tagRules(nodes []ruleNode) []TagRule {
+	if nodes == nil {
+		return nil
+	}
+	rules := make([]TagRule, len(nodes))
+	for i, node := range nodes {
+		rules[i] = TagRule{Name: node.name, Params: node.params, Offset: node.offset, Args: tagRules(node.args)}
+	}
+	return rules
+}
+
+// parseTag tokenizes a tag into its rules
+func parseTag(tag string) ([]ruleNode, error) {
+	p := &tagParser{tag: tag}
+	var nodes []ruleNode
+	for {
+		node, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		if p.eof() {
+			return nodes, nil
+		}
+		if p.tag[p.pos] != ',' {
+			return nil, p.errorf(p.pos, "unexpected %q", p.tag[p.pos])
+		}
+		p.pos++ // consume ','
+	}
+}
+
+// tagParser is a cursor over a tag being parsed
+type tagParser struct {
+	tag   string
+	pos   int
+	depth int // number of open not( parentheses
+}
+
+func (p *tagParser) eof() bool {
+	return p.pos >= len(p.tag)
+}
+
+func (p *tagParser) errorf(offset int, format string, args ...interface{}) error {
+	return &tagPosError{offset: offset, msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *tagParser) skipSpaces() {
+	for !p.eof() && p.tag[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// closes reports whether c ends a rule or parameter at the current position
+func (p *tagParser) closes(c byte) bool {
+	return c == ',' || c == '|' || (c == ')' && p.depth > 0)
+}
+
+// expr parses a term or alternatives separated by '|'
+func (p *tagParser) expr() (ruleNode, error) {
+	first, err := p.term()
+	if err != nil || p.eof() || p.tag[p.pos] != '|' {
+		return first, err
+	}
+	node := ruleNode{name: orRule, offset: first.offset, args: []ruleNode{first}}
+	for !p.eof() && p.tag[p.pos] == '|' {
+		p.pos++ // consume '|'
+		alt, err := p.term()
+		if err != nil {
+			return node, err
+		}
+		node.args = append(node.args, alt)
+	}
+	return node, nil
+}
+
+// term parses a negation or a single rule
+func (p *tagParser) term() (ruleNode, error) {
+	p.skipSpaces()
+	if !strings.HasPrefix(p.tag[p.pos:], notRule+"(") {
+		return p.rule()
+	}
+
+	node := ruleNode{name: notRule, offset: p.pos}
+	p.pos += len(notRule) + 1
+	p.depth++
+	start := p.pos
+	operand, err := p.expr()
+	if err != nil {
+		return node, err
+	}
+	if p.eof() || p.tag[p.pos] != ')' {
+		return node, p.errorf(node.offset, "not( is not closed by ')'")
+	}
+	node.args = []ruleNode{operand}
+	node.text = strings.TrimSpace(p.tag[start:p.pos])
+	p.pos++ // consume ')'
+	p.depth--
+	p.skipSpaces()
+	return node, nil
+}
+
+// rule parses a rule name and its parameters, stopping before the next ',', '|' or ')'
+func (p *tagParser) rule() (ruleNode, error) {
+	p.skipSpaces()
+	node := ruleNode{offset: p.pos}
+	start := p.pos
+	for !p.eof() && !p.closes(p.tag[p.pos]) && p.tag[p.pos] != '=' {
+		if c := p.tag[p.pos]; c == '\'' || c == '\\' || c == '(' {
+			return node, p.errorf(p.pos, "unexpected %q in rule name", c)
+		}
+		p.pos++
+	}
+	node.name = strings.TrimSpace(p.tag[start:p.pos])
+	if node.name == "" {
+		return node, p.errorf(start, "empty rule")
+	}
+	if strings.Contains(node.name, " ") {
+		return node, p.errorf(start, "rule name %q contains spaces", node.name)
+	}
+	if p.eof() || p.closes(p.tag[p.pos]) {
+		return node, nil
+	}
+
+	p.pos++ // consume '='
+	for {
+		p.skipSpaces()
+		if p.eof() || p.closes(p.tag[p.pos]) {
+			break
+		}
+		param, err := p.param()
+		if err != nil {
+			return node, err
+		}
+		node.params = append(node.params, param)
+	}
+	if node.params == nil {
+		return node, p.errorf(p.pos, "missing parameters for %s", node.name)
+	}
+	return node, nil
+}
+
+// param parses a bare or quoted parameter
+func (p *tagParser) param() (string, error) {
+	var sb strings.Builder
+	if p.tag[p.pos] == '\'' {
+		start := p.pos
+		p.pos++
+		for {
+			if p.eof() {
+				return "", p.errorf(start, "unterminated quoted parameter")
+			}
+			c := p.tag[p.pos]
+			p.pos++
+			switch c {
+			case '\'':
+				if !p.eof() && p.tag[p.pos] != ' ' && !p.closes(p.tag[p.pos]) {
+					return "", p.errorf(p.pos, "expected space or ',' after quoted parameter")
+				}
+				return sb.String(), nil
+			case '\\':
+				if p.eof() {
+					return "", p.errorf(p.pos-1, "trailing backslash")
+				}
+				sb.WriteByte(p.tag[p.pos])
+				p.pos++
+			default:
+				sb.WriteByte(c)
+			}
+		}
+	}
+
+	for !p.eof() {
+		c := p.tag[p.pos]
+		if c == ' ' || p.closes(c) {
+			break
+		}
+		switch c {
+		case '\'':
+			return "", p.errorf(p.pos, "unexpected quote inside parameter")
+		case '\\':
+			if p.pos+1 >= len(p.tag) {
+				return "", p.errorf(p.pos, "trailing backslash")
+			}
+			p.pos++
+			c = p.tag[p.pos]
+		}
+		sb.WriteByte(c)
+		p.pos++
+	}
+	return sb.String(), nil
+}
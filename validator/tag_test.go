@@ -0,0 +1,195 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Test tokenizing tags into rules and parameters
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected []ruleNode
+	}{
+		{"required", []ruleNode{{name: "required"}}},
+		{"required, email", []ruleNode{{name: "required"}, {name: "email", offset: 10}}},
+		{"between=2024-01-01 2024-12-31 2006-01-02", []ruleNode{{name: "between", params: []string{"2024-01-01", "2024-12-31", "2006-01-02"}}}},
+		{"after='2024-01-01 10:00' '2006-01-02 15:04',required", []ruleNode{
+			{name: "after", params: []string{"2024-01-01 10:00", "2006-01-02 15:04"}},
+			{name: "required", offset: 44},
+		}},
+		{`oneof=a\,b 'it\'s' ''`, []ruleNode{{name: "oneof", params: []string{"a,b", "it's", ""}}}},
+		{"email|e164,required", []ruleNode{
+			{name: orRule, args: []ruleNode{{name: "email"}, {name: "e164", offset: 6}}},
+			{name: "required", offset: 11},
+		}},
+		{"not(eqfield=Password|min=3 'a|b'), email", []ruleNode{
+			{name: notRule, text: "eqfield=Password|min=3 'a|b'", args: []ruleNode{{name: orRule, offset: 4, args: []ruleNode{
+				{name: "eqfield", params: []string{"Password"}, offset: 4},
+				{name: "min", params: []string{"3", "a|b"}, offset: 21},
+			}}}},
+			{name: "email", offset: 35},
+		}},
+	}
+
+	for _, test := range tests {
+		nodes, err := parseTag(test.tag)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.tag, err)
+			continue
+		}
+		if !reflect.DeepEqual(nodes, test.expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.tag, test.expected, nodes)
+		}
+	}
+}
+
+// Test the exported form of parsed tags and its errors
+func TestParseTagExported(t *testing.T) {
+	rules, err := ParseTag("omitempty,email|e164,min=3")
+	expected := []TagRule{
+		{Name: "omitempty"},
+		{Name: orRule, Offset: 10, Args: []TagRule{{Name: "email", Offset: 10}, {Name: "e164", Offset: 16}}},
+		{Name: "min", Params: []string{"3"}, Offset: 21},
+	}
+	if err != nil || !reflect.DeepEqual(rules, expected) {
+		t.Errorf("expected %+v, got %+v (%v)", expected, rules, err)
+	}
+
+	_, err = ParseTag("required,")
+	var tagErr *TagError
+	if !errors.As(err, &tagErr) || tagErr.Offset != 9 || err.Error() != "invalid validate tag at offset 9: empty rule" {
+		t.Errorf("expected a positioned TagError, got %v", err)
+	}
+}
+
+// Test checking tags without a struct, as linters do
+func TestCheckTag(t *testing.T) {
+	v := New()
+	if err := v.RegisterRule("even", func(string, interface{}, ...string) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	resolve := func(path string) error {
+		if path != "Password" {
+			return fmt.Errorf("unknown field %q", path)
+		}
+		return nil
+	}
+	tests := []struct {
+		tag      string
+		expected string
+	}{
+		{"required,email|e164,even", ""},
+		{"omitempty,date=2006-01-02,after=today 2006-01-02", ""},
+		{"eqfield=Password,required_if=Password x", ""},
+		{"dive,keys,email,endkeys,required", ""},
+		{"requierd", `invalid validate tag at offset 0: unknown validation rule "requierd"`},
+		{"required,minSize=abc", `invalid validate tag at offset 9: minSize: invalid integer parameter "abc"`},
+		{"eqfield=Pasword", `invalid validate tag at offset 0: eqfield: unknown field "Pasword"`},
+		{"email,date=YYYY-MM-DD", `invalid validate tag at offset 6: invalid date layout "YYYY-MM-DD": use the reference time, e.g. 2006-01-02`},
+		{"not(past=dd/mm/yyyy)", `invalid validate tag at offset 4: invalid date layout "dd/mm/yyyy": use the reference time, e.g. 2006-01-02`},
+		{"redact=yes", "invalid validate tag at offset 0: redact takes no parameters"},
+		{"tz=Mars/Olympus", `invalid validate tag at offset 0: tz: unknown time zone "Mars/Olympus"`},
+	}
+	for _, test := range tests {
+		err := v.CheckTag(test.tag, resolve)
+		if got := fmt.Sprint(err); (err == nil) != (test.expected == "") || (err != nil && got != test.expected) {
+			t.Errorf("%s: expected %q, got %v", test.tag, test.expected, err)
+		}
+	}
+	if err := New().CheckTag("eqfield=Anything", nil); err != nil {
+		t.Errorf("expected field paths to be accepted without resolve, got %v", err)
+	}
+}
+
+// Test positioned syntax errors
+func TestParseTagErrors(t *testing.T) {
+	tests := []struct {
+		tag      string
+		offset   int
+		expected string
+	}{
+		{"required,", 9, "empty rule"},
+		{"required,,email", 9, "empty rule"},
+		{"min=", 4, "missing parameters for min"},
+		{"after='2024-01-01", 6, "unterminated quoted parameter"},
+		{"after=a'b'", 7, "unexpected quote inside parameter"},
+		{"min=1\\", 5, "trailing backslash"},
+		{"my rule", 0, `rule name "my rule" contains spaces`},
+		{"email|", 6, "empty rule"},
+		{"not(email", 0, "not( is not closed by ')'"},
+		{"not(email,e164)", 0, "not( is not closed by ')'"},
+		{"nope(email)", 4, `unexpected '(' in rule name`},
+	}
+
+	for _, test := range tests {
+		_, err := parseTag(test.tag)
+		var pos *tagPosError
+		if !errors.As(err, &pos) {
+			t.Errorf("%s: expected a tag error, got %v", test.tag, err)
+			continue
+		}
+		if pos.offset != test.offset || pos.msg != test.expected {
+			t.Errorf("%s: expected '%s' at %d, got '%s' at %d", test.tag, test.expected, test.offset, pos.msg, pos.offset)
+		}
+	}
+}
+
+type testPeriod struct {
+	Start string `validate:"between=2024-01-01 2024-12-31 2006-01-02"`
+	At    string `validate:"after='2024-01-01 10:00' '2006-01-02 15:04'"`
+}
+
+type testBadTags struct {
+	Legacy  string `validate:"between=2024-01-01,2024-12-31,2006-01-02"`
+	Arity   string `validate:"date=2006-01-02 15:04"`
+	Size    []int  `validate:"minSize=abc"`
+	Keys    []int  `validate:"dive,keys,required,endkeys"`
+	Scalar  int    `validate:"dive,required"`
+	Valid   string `validate:"required"`
+	Ignored string `validate:"-"`
+}
+
+// Test multi-parameter rules through struct tags
+func TestValidateMultiParameterTags(t *testing.T) {
+	assertErrors(t, Validate(testPeriod{Start: "2024-06-01", At: "2024-01-01 10:30"}), nil)
+	assertErrors(t, Validate(testPeriod{Start: "2025-06-01", At: "2024-01-01 09:30"}), []string{
+		"Start: Start must be between 2024-01-01 and 2024-12-31",
+		"At: At must be after 2024-01-01 10:00",
+	})
+}
+
+// Test that malformed tags are reported once per field when the plan is compiled
+func TestCompileTagErrors(t *testing.T) {
+	err := New().Compile(&testBadTags{})
+	var tagErrs []*TagError
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var tagErr *TagError
+		if errors.As(e, &tagErr) {
+			tagErrs = append(tagErrs, tagErr)
+		}
+	}
+
+	expected := []string{
+		`invalid validate tag on validator.testBadTags.Legacy at offset 0: between: expected 2 to 3 parameter(s), got 1 (parameters are separated by spaces, not commas)`,
+		`invalid validate tag on validator.testBadTags.Arity at offset 0: date: expected 1 parameter(s), got 2 (quote parameters containing spaces)`,
+		`invalid validate tag on validator.testBadTags.Size at offset 0: minSize: invalid integer parameter "abc"`,
+		`invalid validate tag on validator.testBadTags.Keys at offset 5: keys can only be used when diving into a map`,
+		`invalid validate tag on validator.testBadTags.Scalar at offset 0: dive can only be applied to slices, arrays and maps, got int`,
+	}
+	if len(tagErrs) != len(expected) {
+		t.Fatalf("expected %d tag errors, got %d: %v", len(expected), len(tagErrs), err)
+	}
+	for i, tagErr := range tagErrs {
+		if tagErr.Error() != expected[i] {
+			t.Errorf("expected error '%s', got '%s'", expected[i], tagErr.Error())
+		}
+	}
+
+	errs := Validate(testBadTags{})
+	if len(errs) != len(expected)+1 || errs[len(errs)-1].Error() != "Valid: Valid is required" {
+		t.Errorf("expected tag errors followed by field errors, got %v", errs)
+	}
+}
@@ -1,65 +1,492 @@
 package validator
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// Validate validates the fields of a struct based on tags
+// defaultTagName is the struct tag read by a Validator unless configured otherwise
+const defaultTagName = "validate"
+
+// Validator validates structs against its own registry of rules.
+// A Validator is safe for concurrent use.
+type Validator struct {
+	mu        sync.RWMutex
+	rules     map[string]ruleEntry
+	tagName   string
+	clock     Clock
+	location  *time.Location
+	fieldName FieldNameFunc
+
+	// translator renders messages in locale, or in the locale of the call's context
+	translator *Translator
+	locale     string
+
+	// fallback looks up unknown rules in ValidationRules, for the default Validator
+	fallback bool
+
+	// limits bound the errors of calls whose context carries none
+	limits ErrorLimits
+
+	structValidators map[reflect.Type][]StructValidator
+	groupSequences   map[string][]string
+	fieldTags        map[reflect.Type]map[int]string    // rules declared with a Builder, by field index
+	builderStructs   map[reflect.Type][]StructValidator // struct validations declared with a Builder
+
+	// plans caches a *structPlan per reflect.Type; gen invalidates them on configuration changes
+	plans sync.Map
+	gen   atomic.Uint64
+}
+
+// Option configures a Validator created by New
+type Option func(*Validator)
+
+// WithTagName makes the Validator read rules from the given struct tag instead of "validate"
+func WithTagName(name string) Option {
+	return func(v *Validator) {
+		v.tagName = name
+	}
+}
+
+// WithRule registers an additional rule on the Validator. Like RegisterRule it rejects nil
+// rules and invalid or reserved names; as options cannot report errors, New panics on them.
+func WithRule(name string, rule Rule) Option {
+	return func(v *Validator) {
+		if err := checkRule(name, rule == nil); err != nil {
+			panic("validator: " + err.Error())
+		}
+		v.rules[name] = ruleEntry{rule: rule}
+	}
+}
+
+// WithFieldRule registers an additional context-aware rule on the Validator.
+// New panics on the rules RegisterFieldRule rejects.
+func WithFieldRule(name string, rule FieldRule) Option {
+	return func(v *Validator) {
+		if err := checkRule(name, rule == nil); err != nil {
+			panic("validator: " + err.Error())
+		}
+		v.rules[name] = ruleEntry{fieldRule: rule}
+	}
+}
+
+// New creates a Validator with the predefined rules and the given options applied
+func New(opts ...Option) *Validator {
+	v := &Validator{
+		rules:            make(map[string]ruleEntry, len(ValidationRules)),
+		tagName:          defaultTagName,
+		clock:            SystemClock,
+		location:         time.Local,
+		translator:       DefaultTranslator,
+		structValidators: make(map[reflect.Type][]StructValidator),
+		groupSequences:   make(map[string][]string),
+		fieldTags:        make(map[reflect.Type]map[int]string),
+		builderStructs:   make(map[reflect.Type][]StructValidator),
+	}
+	for name, rule := range ValidationRules {
+		v.rules[name] = ruleEntry{rule: rule, compile: ruleCompilers[name], predefined: true}
+	}
+	for name, compile := range ruleCompilers {
+		if _, exists := v.rules[name]; !exists {
+			v.rules[name] = ruleEntry{compile: compile, predefined: true}
+		}
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// defaultValidator backs the package-level functions. It also looks up rules in ValidationRules,
+// for code that adds rules to the map or replaces predefined ones.
+var defaultValidator = New(func(v *Validator) { v.fallback = true })
+
+// Validate validates s with the default Validator
 func Validate(s interface{}) ValidationErrors {
+	return defaultValidator.Validate(s)
+}
+
+// RegisterRule registers a rule on the default Validator
+func RegisterRule(name string, rule Rule) error {
+	return defaultValidator.RegisterRule(name, rule)
+}
+
+// RegisterFieldRule registers a context-aware rule on the default Validator
+func RegisterFieldRule(name string, rule FieldRule) error {
+	return defaultValidator.RegisterFieldRule(name, rule)
+}
+
+// ValidateContext validates s with the default Validator, passing ctx to context-aware rules
+func ValidateContext(ctx context.Context, s interface{}) ValidationErrors {
+	return defaultValidator.ValidateContext(ctx, s)
+}
+
+// RegisterRule adds or replaces a rule available to this Validator
+func (v *Validator) RegisterRule(name string, rule Rule) error {
+	if err := checkRule(name, rule == nil); err != nil {
+		return err
+	}
+	return v.register(name, ruleEntry{rule: rule})
+}
+
+// RegisterFieldRule adds or replaces a rule that receives the full FieldContext,
+// giving it access to the parent struct, the top-level value and the context
+func (v *Validator) RegisterFieldRule(name string, rule FieldRule) error {
+	if err := checkRule(name, rule == nil); err != nil {
+		return err
+	}
+	return v.register(name, ruleEntry{fieldRule: rule})
+}
+
+// checkRule validates the name of a rule being registered and that the rule is set
+func checkRule(name string, isNil bool) error {
+	if isNil {
+		return fmt.Errorf("rule %q must not be nil", name)
+	}
+	if name == "" || strings.ContainsAny(name, ",= '\\|()") {
+		return fmt.Errorf("invalid rule name %q", name)
+	}
+	if isReservedRuleName(name) {
+		return fmt.Errorf("rule name %q is reserved", name)
+	}
+	return nil
+}
+
+// register stores a checked rule under its name
+func (v *Validator) register(name string, entry ruleEntry) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = entry
+	v.invalidatePlans()
+	return nil
+}
+
+// SetTagName changes the struct tag the Validator reads rules from
+func (v *Validator) SetTagName(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tagName = name
+	v.invalidatePlans()
+}
+
+// TagName returns the struct tag the Validator reads rules from
+func (v *Validator) TagName() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.tagName
+}
+
+// Validate validates the fields of a struct based on tags.
+// Nested struct values, non-nil struct pointers and embedded structs are
+// validated recursively; errors are reported with their full path (e.g. Address.ZipCode).
+// Struct-level validation (Validatable, ReportValidatable and registered
+// StructValidators) runs after the fields of each struct.
+func (v *Validator) Validate(s interface{}) ValidationErrors {
+	return v.ValidateContext(context.Background(), s)
+}
+
+// ValidateContext is like Validate, passing ctx to context-aware rules through FieldContext
+func (v *Validator) ValidateContext(ctx context.Context, s interface{}) ValidationErrors {
+	return v.ValidateGroupsContext(ctx, s)
+}
+
+// validate walks val once, checking the fields of the given groups. val is a struct,
+// or a slice, array or map whose struct elements are each validated as a top-level struct.
+func (v *Validator) validate(ctx context.Context, val reflect.Value, groups []string) ValidationErrors {
 	var errs ValidationErrors
-	val := reflect.ValueOf(s)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
+	locale := LocaleFromContext(ctx)
+	if locale == "" {
+		locale = v.locale
+	}
+	limits := v.errorLimits(ctx)
+	walk := func(top reflect.Value, path fieldPath) {
+		if limits.full(errs) {
+			return
+		}
+		w := &walker{v: v, ctx: ctx, groups: groups, locale: locale, limits: limits, top: top}
+		w.validateStruct(top, path, &errs)
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		walk(val, rootPath)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if elem, ok := structValue(val.Index(i)); ok {
+				walk(elem, rootPath.index(i))
+			}
+		}
+	case reflect.Map:
+		keys, names := sortedKeys(val)
+		for i, key := range keys {
+			if elem, ok := structValue(val.MapIndex(key)); ok {
+				walk(elem, rootPath.key(names[i]))
+			}
+		}
 	}
-	if val.Kind() != reflect.Struct {
-		panic("validator.Validate: input must be a struct or a pointer to a struct")
+	return limits.truncate(errs)
+}
+
+// invalidatePlans discards the cached plans after a configuration change.
+// The caller must hold v.mu for writing.
+func (v *Validator) invalidatePlans() {
+	v.gen.Add(1)
+	v.plans.Clear()
+}
+
+// isReservedRuleName reports whether name is a tag keyword handled by the engine itself
+func isReservedRuleName(name string) bool {
+	switch name {
+	case "dive", "keys", "endkeys", "tz", "groups", "when", "omitempty", "omitnil", "redact", "msg", notRule, "-":
+		return true
 	}
+	return false
+}
 
-	typ := val.Type()
-	for i := 0; i < val.NumField(); i++ {
-		field := typ.Field(i)
-		value := val.Field(i)
+// visitKey identifies a struct reached through a pointer, used to break reference cycles
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// walker holds the state of a single Validate call
+type walker struct {
+	v        *Validator
+	ctx      context.Context
+	groups   []string      // groups being validated
+	locale   string        // locale of the messages, "" for the default messages
+	limits   ErrorLimits   // limits of the call
+	top      reflect.Value // struct passed to Validate
+	visiting map[visitKey]bool
+	fc       FieldContext
+}
 
-		// Skip unexported fields
-		if !value.CanInterface() {
+// report appends an error about the value at path. field is nil for errors not tied to a field.
+func (w *walker) report(errs *ValidationErrors, path fieldPath, field *fieldPlan, code string, params []string, value interface{}, message string) {
+	value = w.reportedValue(field, value)
+	*errs = append(*errs, ValidationError{
+		Field:     path.name,
+		Message:   message,
+		Code:      code,
+		Namespace: joinPath(w.top.Type().Name(), path.name),
+		JSONPath:  path.json,
+		Params:    slices.Clone(params),
+		Value:     value,
+	})
+}
+
+// reportedValue returns the value reported for field, hiding it if the field is redacted
+func (w *walker) reportedValue(field *fieldPlan, value interface{}) interface{} {
+	if field != nil && field.redact && value != nil {
+		return Redacted
+	}
+	return value
+}
+
+// validateStruct applies the plan of val's type to every field, descends into nested structs
+// and then runs the struct-level validation
+func (w *walker) validateStruct(val reflect.Value, prefix fieldPath, errs *ValidationErrors) {
+	plan := w.v.structPlan(val.Type())
+	for i := range plan.fields {
+		if w.limits.full(*errs) {
+			return
+		}
+		field := &plan.fields[i]
+		value := val.Field(field.index)
+
+		// Embedded structs promote their fields to the parent path, like Go does,
+		// unless a json tag nests them in the JSON form
+		if field.embedded {
+			if nested, ok := structValue(value); ok {
+				embedded := prefix
+				if field.jsonNested {
+					embedded.json += "." + field.jsonName
+				}
+				w.descend(value, nested, embedded, errs)
+			}
+			if !field.exported {
+				continue
+			}
+		}
+
+		path := prefix.field(field.name, field.jsonName)
+
+		// Fields with malformed tags report the tag error instead of being validated
+		if field.err != nil {
+			w.report(errs, path, field, CodeInvalidTag, nil, nil, field.err.Error())
 			continue
 		}
 
-		// Parse "validate" tag
-		tag := field.Tag.Get("validate")
-		if tag == "" {
+		// Apply validation rules of the requested groups; a "dive" hands the remaining rules to the elements
+		// and omitted values are not descended into
+		if field.rules != nil && inGroups(field.groups, w.groups) && w.applyPlan(path, val, value, field, field.rules, errs) {
 			continue
 		}
 
-		// Apply validation rules
-		ruleList := strings.Split(tag, ",")
-		for _, rule := range ruleList {
-			ruleName, params := parseRule(rule)
-			if ruleFunc, exists := ValidationRules[ruleName]; exists {
-				if err := ruleFunc(field.Name, value.Interface(), params...); err != nil {
-					errs = append(errs, ValidationError{
-						Field:   field.Name,
-						Message: err.Error(),
-					})
-				}
-			} else {
-				errs = append(errs, ValidationError{
-					Field:   field.Name,
-					Message: fmt.Sprintf("unknown validation rule: %s", ruleName),
-				})
+		if field.nested && !field.embedded {
+			if nested, ok := structValue(value); ok {
+				w.descend(value, nested, path, errs)
+			}
+		}
+	}
+	if !w.limits.full(*errs) {
+		w.validateStructLevel(val, prefix, plan, errs)
+	}
+}
+
+// applyPlan runs compiled rules against value, then the rules guarded by "when" if its condition
+// holds, then dives into its elements if requested. parent is the struct holding field.
+// It reports whether the value was fully handled, by a dive or by omitempty/omitnil,
+// in which case a nested struct is not descended into.
+func (w *walker) applyPlan(path fieldPath, parent, value reflect.Value, field *fieldPlan, plan *rulePlan, errs *ValidationErrors) bool {
+	if plan.omit != omitNone {
+		if plan.omit.skips(value) {
+			return true
+		}
+		// Optional pointers are validated through the value they point to
+		if value.Kind() == reflect.Ptr {
+			value = value.Elem()
+		}
+	}
+	if len(plan.rules) > 0 {
+		w.fc = FieldContext{
+			ctx:    w.ctx,
+			Value:  value.Interface(),
+			Path:   path.name,
+			Field:  field.field,
+			Tag:    field.tag,
+			Parent: parent,
+			Top:    w.top,
+		}
+		for _, rule := range plan.rules {
+			if w.stops(*errs, path) {
+				break
+			}
+			w.fc.Rule, w.fc.Params = rule.name, rule.params
+			if err := rule.check(&w.fc); err != nil {
+				message := w.message(path.name, parent.Type(), rule, w.reportedValue(field, w.fc.Value), err)
+				w.report(errs, path, field, rule.name, rule.params, w.fc.Value, message)
 			}
 		}
 	}
-	return errs
+	if plan.when != nil {
+		if !plan.when.cond(parent) {
+			return false
+		}
+		return w.applyPlan(path, parent, value, field, plan.when.rules, errs)
+	}
+	if plan.dive != nil {
+		w.dive(path, parent, value, field, plan.dive, errs)
+		return true
+	}
+	return false
+}
+
+// dive applies the element rules to every element of a slice, array or map,
+// and the key rules to every key of a map
+func (w *walker) dive(path fieldPath, parent, value reflect.Value, field *fieldPlan, dive *divePlan, errs *ValidationErrors) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if dive.keys != nil {
+			w.report(errs, path, field, CodeDive, nil, nil, "keys can only be used when diving into a map")
+			return
+		}
+		for i := 0; i < value.Len() && !w.limits.full(*errs); i++ {
+			w.validateElement(path.index(i), parent, value.Index(i), field, dive.elem, errs)
+		}
+	case reflect.Map:
+		keys, names := sortedKeys(value)
+		for i := 0; i < len(keys) && !w.limits.full(*errs); i++ {
+			elemPath := path.key(names[i])
+			if dive.keys != nil {
+				w.applyPlan(elemPath, parent, keys[i], field, dive.keys, errs)
+			}
+			w.validateElement(elemPath, parent, value.MapIndex(keys[i]), field, dive.elem, errs)
+		}
+	default:
+		w.report(errs, path, field, CodeDive, nil, nil, fmt.Sprintf("dive can only be applied to slices, arrays and maps, got %s", value.Kind()))
+	}
+}
+
+// validateElement applies rules to a collection element and validates it recursively when it is a struct
+func (w *walker) validateElement(path fieldPath, parent, elem reflect.Value, field *fieldPlan, plan *rulePlan, errs *ValidationErrors) {
+	if elem.Kind() == reflect.Interface && !elem.IsNil() {
+		elem = elem.Elem()
+	}
+	if plan != nil && w.applyPlan(path, parent, elem, field, plan, errs) {
+		return
+	}
+	if nested, ok := structValue(elem); ok {
+		w.descend(elem, nested, path, errs)
+	}
+}
+
+// descend validates a nested struct, skipping structs already being validated through a pointer cycle
+func (w *walker) descend(value, nested reflect.Value, prefix fieldPath, errs *ValidationErrors) {
+	if value.Kind() == reflect.Ptr {
+		key := visitKey{ptr: value.Pointer(), typ: nested.Type()}
+		if w.visiting[key] {
+			return
+		}
+		if w.visiting == nil {
+			w.visiting = make(map[visitKey]bool)
+		}
+		w.visiting[key] = true
+		defer delete(w.visiting, key)
+	}
+	w.validateStruct(nested, prefix, errs)
+}
+
+// sortedKeys returns the keys of a map sorted by their printed form, and those names
+func sortedKeys(value reflect.Value) ([]reflect.Value, []string) {
+	keys := value.MapKeys()
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = fmt.Sprint(key.Interface())
+	}
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return names[order[i]] < names[order[j]]
+	})
+	sortedKeys, sortedNames := make([]reflect.Value, len(keys)), make([]string, len(keys))
+	for i, j := range order {
+		sortedKeys[i], sortedNames[i] = keys[j], names[j]
+	}
+	return sortedKeys, sortedNames
+}
+
+// structValue returns the struct held by a struct value or a non-nil struct pointer
+func structValue(value reflect.Value) (reflect.Value, bool) {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		value = value.Elem()
+	}
+	return value, value.Kind() == reflect.Struct
 }
 
-// parseRule splits a rule into its name and parameters
-func parseRule(rule string) (string, []string) {
-	parts := strings.SplitN(rule, "=", 2)
-	if len(parts) == 2 {
-		return parts[0], strings.Split(parts[1], ",")
+// joinPath appends a field name to a dotted path
+func joinPath(prefix, name string) string {
+	if prefix == "" || strings.HasPrefix(name, "[") {
+		return prefix + name
 	}
-	return parts[0], nil
+	return prefix + "." + name
 }
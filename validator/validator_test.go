@@ -1,8 +1,13 @@
 package validator
 
 import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+	_ "time/tzdata"
 )
 
 // Test required rule
@@ -445,6 +450,428 @@ func TestSizeRule(t *testing.T) {
 	}
 }
 
+type testAddress struct {
+	Street  string `validate:"required"`
+	ZipCode string `validate:"required,minSize=5"`
+}
+
+type testAudit struct {
+	CreatedBy string `validate:"required"`
+}
+
+type testNode struct {
+	Name string `validate:"required"`
+	Next *testNode
+}
+
+type testCustomer struct {
+	testAudit
+	Name     string `validate:"required"`
+	Address  testAddress
+	Billing  *testAddress
+	Shipping *testAddress
+	Ignored  testAddress `validate:"-"`
+}
+
+// Test nested, pointer and embedded struct validation
+func TestValidateNestedStructs(t *testing.T) {
+	customer := testCustomer{
+		Name:    "John",
+		Address: testAddress{Street: "Main St", ZipCode: "123"},
+		Billing: &testAddress{ZipCode: "12345"},
+	}
+
+	errs := Validate(customer)
+	expected := []string{
+		"CreatedBy: CreatedBy is required",
+		"Address.ZipCode: Address.ZipCode must have at least 5 elements",
+		"Billing.Street: Billing.Street is required",
+	}
+	assertErrors(t, errs, expected)
+}
+
+// Test that reference cycles do not recurse forever
+func TestValidatePointerCycle(t *testing.T) {
+	node := &testNode{Name: "a"}
+	node.Next = &testNode{Next: node}
+
+	errs := Validate(node)
+	assertErrors(t, errs, []string{"Next.Name: Next.Name is required"})
+}
+
+type testCatalog struct {
+	Tags    []string             `validate:"minSize=1,dive,required,minSize=2"`
+	Items   []testAddress        `validate:"dive"`
+	Matrix  [][]int              `validate:"dive,dive,positive"`
+	Labels  map[string]string    `validate:"dive,keys,minSize=3,endkeys,required"`
+	Offices map[string]*testNode `validate:"dive,required"`
+}
+
+// Test dive into slices, arrays and maps
+func TestValidateDive(t *testing.T) {
+	catalog := testCatalog{
+		Tags:    []string{"go", "", "x"},
+		Items:   []testAddress{{Street: "Main St", ZipCode: "12345"}, {ZipCode: "12345"}},
+		Matrix:  [][]int{{1, 2}, {3, -4}},
+		Labels:  map[string]string{"env": "", "region": "eu"},
+		Offices: map[string]*testNode{"hq": nil, "lab": {}},
+	}
+
+	errs := Validate(catalog)
+	expected := []string{
+		"Tags[1]: Tags[1] is required",
+		"Tags[1]: Tags[1] must have at least 2 elements",
+		"Tags[2]: Tags[2] must have at least 2 elements",
+		"Items[1].Street: Items[1].Street is required",
+		"Matrix[1][1]: Matrix[1][1] must be positive",
+		"Labels[env]: Labels[env] is required",
+		"Offices[hq]: Offices[hq] is required",
+		"Offices[lab].Name: Offices[lab].Name is required",
+	}
+	assertErrors(t, errs, expected)
+}
+
+type testOptional struct {
+	MiddleName *string       `validate:"omitnil,minSize=2"`
+	Nickname   string        `validate:"omitempty,minSize=3"`
+	Age        int           `validate:"omitempty,positive"`
+	Referral   *string       `validate:"omitempty,email"`
+	Billing    *testAddress  `validate:"omitnil"`
+	Shipping   testAddress   `validate:"omitempty"`
+	Aliases    []string      `validate:"omitnil,dive,omitempty,minSize=2"`
+	Backup     []testAddress `validate:"omitempty,dive"`
+}
+
+// Test that omitempty and omitnil skip the rules of absent values
+func TestValidateOmitEmpty(t *testing.T) {
+	assertErrors(t, Validate(testOptional{}), nil)
+
+	empty, short := "", "J"
+	assertErrors(t, Validate(testOptional{
+		MiddleName: &short,
+		Nickname:   "Jo",
+		Age:        -1,
+		Referral:   &empty,
+		Billing:    &testAddress{},
+		Shipping:   testAddress{ZipCode: "123"},
+		Aliases:    []string{"", "x"},
+	}), []string{
+		"MiddleName: MiddleName must have at least 2 elements",
+		"Nickname: Nickname must have at least 3 elements",
+		"Age: Age must be positive",
+		"Billing.Street: Billing.Street is required",
+		"Billing.ZipCode: Billing.ZipCode is required",
+		"Billing.ZipCode: Billing.ZipCode must have at least 5 elements",
+		"Shipping.Street: Shipping.Street is required",
+		"Shipping.ZipCode: Shipping.ZipCode must have at least 5 elements",
+		"Aliases[1]: Aliases[1] must have at least 2 elements",
+	})
+
+	err := New().Compile(struct {
+		Name string `validate:"omitempty,omitnil"`
+	}{})
+	if err == nil || !strings.HasSuffix(err.Error(), "at offset 10: only one of omitempty and omitnil can be used") {
+		t.Errorf("expected a conflicting modifiers error, got %v", err)
+	}
+}
+
+type testEven struct {
+	Count int `validate:"even" check:"positive"`
+}
+
+func evenRule(fieldName string, value interface{}, _ ...string) error {
+	if v, ok := value.(int); ok && v%2 != 0 {
+		return fmt.Errorf("%s must be even", fieldName)
+	}
+	return nil
+}
+
+// Test that rules registered on one Validator do not leak into others
+func TestValidatorRegistryIsolation(t *testing.T) {
+	v := New()
+	if err := v.RegisterRule("even", evenRule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertErrors(t, v.Validate(testEven{Count: 3}), []string{"Count: Count must be even"})
+	assertErrors(t, New().Validate(testEven{Count: 3}), []string{"Count: invalid validate tag on validator.testEven.Count at offset 0: unknown validation rule \"even\""})
+	assertErrors(t, New(WithTagName("check")).Validate(testEven{Count: -1}), []string{"Count: Count must be positive"})
+
+	if err := v.RegisterRule("dive", evenRule); err == nil {
+		t.Errorf("expected an error registering a reserved rule name")
+	}
+}
+
+type testOdd struct {
+	Count int `validate:"odd"`
+}
+
+type testOddEmail struct {
+	Count int    `validate:"odd"`
+	Email string `validate:"email"`
+}
+
+// Test that the package-level functions honor rules added to or replaced in ValidationRules
+func TestValidationRulesFallback(t *testing.T) {
+	// Misses are not cached, so rules added after a first validation are found
+	assertErrors(t, Validate(testOddEmail{Count: 2, Email: "a@b.co"}), []string{
+		"Count: invalid validate tag on validator.testOddEmail.Count at offset 0: unknown validation rule \"odd\"",
+	})
+
+	v := New()
+	email := ValidationRules["email"]
+	ValidationRules["email"] = func(fieldName string, value interface{}, _ ...string) error {
+		if s, _ := value.(string); !strings.HasSuffix(s, ".org") {
+			return fmt.Errorf("%s must be an .org address", fieldName)
+		}
+		return nil
+	}
+	defer func() {
+		ValidationRules["email"] = email
+		delete(ValidationRules, "odd")
+		// Plans compiled with the replaced rules stay cached otherwise
+		defaultValidator.mu.Lock()
+		defaultValidator.invalidatePlans()
+		defaultValidator.mu.Unlock()
+	}()
+	ValidationRules["odd"] = func(fieldName string, value interface{}, _ ...string) error {
+		if v, ok := value.(int); ok && v%2 == 0 {
+			return fmt.Errorf("%s must be odd", fieldName)
+		}
+		return nil
+	}
+
+	assertErrors(t, Validate(testOdd{Count: 2}), []string{"Count: Count must be odd"})
+	assertErrors(t, Validate(testOddEmail{Count: 2, Email: "a@b.co"}), []string{
+		"Count: Count must be odd",
+		"Email: Email must be an .org address",
+	})
+	assertErrors(t, v.Validate(testOddEmail{Count: 1, Email: "a@b.co"}), []string{
+		"Count: invalid validate tag on validator.testOddEmail.Count at offset 0: unknown validation rule \"odd\"",
+	})
+	assertErrors(t, v.Validate(testOdd{Count: 2}), []string{"Count: invalid validate tag on validator.testOdd.Count at offset 0: unknown validation rule \"odd\""})
+}
+
+// Test that options registering rules reject what RegisterRule rejects
+func TestWithRuleChecksName(t *testing.T) {
+	for _, opt := range []Option{WithRule("dive", evenRule), WithRule("a,b", evenRule), WithRule("even", nil), WithFieldRule("omitempty", func(*FieldContext) error { return nil })} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected New to panic")
+				}
+			}()
+			New(opt)
+		}()
+	}
+}
+
+// Test registering rules while validating concurrently
+func TestValidatorConcurrentUse(t *testing.T) {
+	v := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_ = v.RegisterRule(fmt.Sprintf("custom%d", i), evenRule)
+		}(i)
+		go func() {
+			defer wg.Done()
+			v.Validate(testAddress{Street: "Main St", ZipCode: "12345"})
+		}()
+	}
+	wg.Wait()
+}
+
+// Test that cached plans pick up rules registered after the first validation
+func TestValidatorPlanInvalidation(t *testing.T) {
+	v := New()
+	assertErrors(t, v.Validate(testEven{Count: 3}), []string{"Count: invalid validate tag on validator.testEven.Count at offset 0: unknown validation rule \"even\""})
+
+	if err := v.RegisterRule("even", evenRule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertErrors(t, v.Validate(testEven{Count: 3}), []string{"Count: Count must be even"})
+
+	v.SetTagName("check")
+	assertErrors(t, v.Validate(testEven{Count: 3}), nil)
+}
+
+type testSchedule struct {
+	Birthdate    string `validate:"past=2006-01-02"`
+	Joined       string `validate:"pastInclusive=2006-01-02"`
+	Renewal      string `validate:"future=2006-01-02"`
+	Starts       string `validate:"futureInclusive=2006-01-02"`
+	NextCall     string `validate:"future='2006-01-02 15:04'"`
+	LastLoginDay string `validate:"past-inclusive=2006-01-02"`
+}
+
+// Test past and future rules against an injected clock
+func TestTemporalRulesWithClock(t *testing.T) {
+	// 23:30 in UTC-5 is already the next day in UTC
+	zone := time.FixedZone("UTC-5", -5*3600)
+	now := time.Date(2024, 3, 10, 23, 30, 0, 0, zone)
+	v := New(WithClock(ClockFunc(func() time.Time { return now })), WithLocation(zone))
+
+	valid := testSchedule{
+		Birthdate:    "2024-03-09",
+		Joined:       "2024-03-10",
+		Renewal:      "2024-03-11",
+		Starts:       "2024-03-10",
+		NextCall:     "2024-03-10 23:45",
+		LastLoginDay: "2024-03-10",
+	}
+	assertErrors(t, v.Validate(valid), nil)
+
+	invalid := testSchedule{
+		Birthdate:    "2024-03-10",
+		Joined:       "2024-03-11",
+		Renewal:      "2024-03-10",
+		Starts:       "2024-03-09",
+		NextCall:     "2024-03-10 23:15",
+		LastLoginDay: "2024-03-11",
+	}
+	assertErrors(t, v.Validate(invalid), []string{
+		"Birthdate: Birthdate must be in the past",
+		"Joined: Joined must be in the past or today",
+		"Renewal: Renewal must be in the future",
+		"Starts: Starts must be in the future or today",
+		"NextCall: NextCall must be in the future",
+		"LastLoginDay: LastLoginDay must be in the past or today",
+	})
+}
+
+type testZonedSchedule struct {
+	Local  string `validate:"pastInclusive=2006-01-02"`
+	Zoned  string `validate:"tz=Asia/Tokyo,pastInclusive=2006-01-02"`
+	Window string `validate:"tz=Asia/Tokyo,after='2024-03-11 08:00' '2006-01-02 15:04'"`
+}
+
+// Test the default location and the per-field tz modifier
+func TestTemporalRulesTimeZones(t *testing.T) {
+	// 2024-03-10 23:30 UTC is already 2024-03-11 08:30 in Tokyo
+	now := time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC)
+	v := New(WithClock(ClockFunc(func() time.Time { return now })), WithLocation(time.UTC))
+
+	assertErrors(t, v.Validate(testZonedSchedule{
+		Local:  "2024-03-11",
+		Zoned:  "2024-03-11",
+		Window: "2024-03-11 08:15",
+	}), []string{"Local: Local must be in the past or today"})
+
+	err := v.Compile(struct {
+		Day string `validate:"tz=Mars/Olympus,past=2006-01-02"`
+	}{})
+	if err == nil || !strings.Contains(err.Error(), `tz: unknown time zone "Mars/Olympus"`) {
+		t.Errorf("expected an unknown time zone error, got %v", err)
+	}
+}
+
+type testTimeFields struct {
+	Created   time.Time      `validate:"required,past"`
+	Birthday  *time.Time     `validate:"pastInclusive=2006-01-02"`
+	Deleted   sql.NullTime   `validate:"after=2024-01-01 2006-01-02"`
+	Archived  sql.NullTime   `validate:"required"`
+	Window    time.Time      `validate:"between=2024-03-01T00:00:00Z 2024-03-31T00:00:00Z"`
+	Timeout   time.Duration  `validate:"minDuration=1s,maxDuration=1m"`
+	Retention *time.Duration `validate:"maxDuration=720h"`
+}
+
+// Test date rules on time.Time, pointers and sql.NullTime, and duration rules
+func TestTimeValues(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	v := New(WithClock(ClockFunc(func() time.Time { return now })), WithLocation(time.UTC))
+
+	later := now.Add(time.Hour)
+	retention := 1000 * time.Hour
+	errs := v.Validate(testTimeFields{
+		Created:   later,
+		Birthday:  &later,
+		Deleted:   sql.NullTime{Time: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		Window:    time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		Timeout:   90 * time.Second,
+		Retention: &retention,
+	})
+	assertErrors(t, errs, []string{
+		"Created: Created must be in the past",
+		"Deleted: Deleted must be after 2024-01-01",
+		"Archived: Archived is required",
+		"Window: Window must be between 2024-03-01T00:00:00Z and 2024-03-31T00:00:00Z",
+		"Timeout: Timeout must be at most 1m0s",
+		"Retention: Retention must be at most 720h0m0s",
+	})
+
+	errs = v.Validate(testTimeFields{
+		Created:  now.Add(-time.Minute),
+		Archived: sql.NullTime{Time: now, Valid: true},
+		Window:   time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		Timeout:  time.Second,
+	})
+	assertErrors(t, errs, nil)
+
+	// Zero times are absent, like nil pointers, and only fail required
+	errs = v.Validate(testTimeFields{
+		Birthday: &time.Time{},
+		Deleted:  sql.NullTime{Valid: true},
+		Archived: sql.NullTime{Time: now, Valid: true},
+		Timeout:  time.Second,
+	})
+	assertErrors(t, errs, []string{"Created: Created is required"})
+}
+
+type testRelativeDates struct {
+	Birthdate string    `validate:"before=today-18y+1d 2006-01-02"`
+	Delivery  string    `validate:"between=today today+30d 2006-01-02"`
+	Invoice   time.Time `validate:"after=startOfMonth"`
+	Reminder  time.Time `validate:"after=now+1h"`
+}
+
+// Test relative reference dates evaluated against the clock
+func TestRelativeDateExpressions(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	v := New(WithClock(ClockFunc(func() time.Time { return now })), WithLocation(time.UTC))
+
+	assertErrors(t, v.Validate(testRelativeDates{
+		Birthdate: "2006-03-10",
+		Delivery:  "2024-04-09",
+		Invoice:   time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+		Reminder:  now.Add(2 * time.Hour),
+	}), nil)
+
+	assertErrors(t, v.Validate(testRelativeDates{
+		Birthdate: "2006-03-11",
+		Delivery:  "2024-04-10",
+		Invoice:   time.Date(2024, 2, 29, 8, 0, 0, 0, time.UTC),
+		Reminder:  now.Add(30 * time.Minute),
+	}), []string{
+		"Birthdate: Birthdate must be before today-18y+1d",
+		"Delivery: Delivery must be between today and today+30d",
+		"Invoice: Invoice must be after startOfMonth",
+		"Reminder: Reminder must be after now+1h",
+	})
+
+	err := v.Compile(struct {
+		Day string `validate:"after=today+3x 2006-01-02"`
+	}{})
+	if err == nil || !strings.Contains(err.Error(), `invalid relative date "today+3x": unknown unit 'x'`) {
+		t.Errorf("expected an invalid relative date error, got %v", err)
+	}
+}
+
+// Helper function to compare validation errors with their expected strings
+func assertErrors(t *testing.T, errs ValidationErrors, expected []string) {
+	t.Helper()
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for i, err := range errs {
+		if err.Error() != expected[i] {
+			t.Errorf("expected error '%s', got '%s'", expected[i], err.Error())
+		}
+	}
+}
+
 // Helper function to validate expected errors
 func validateError(t *testing.T, err error, expected string) {
 	if expected == "" && err != nil {
@@ -0,0 +1,33 @@
+// Package validatortest helps testing code generated by govalidator-gen.
+package validatortest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/devjefster/GoValidator/validator"
+)
+
+// Generated is implemented by the types govalidator-gen generates a Validate method for
+type Generated interface {
+	Validate() validator.ValidationErrors
+}
+
+// AssertParity fails t when the generated Validate method of a value reports
+// different errors than validator.Validate does for the same value
+func AssertParity(t testing.TB, values ...Generated) {
+	t.Helper()
+	for _, value := range values {
+		got, want := value.Validate(), validator.Validate(value)
+		if len(got) != len(want) {
+			t.Errorf("%T %+v: generated code reported %d errors, the reflective engine %d:\ngenerated: %v\nreflective: %v",
+				value, value, len(got), len(want), got, want)
+			continue
+		}
+		for i := range want {
+			if !reflect.DeepEqual(got[i], want[i]) {
+				t.Errorf("%T %+v: error %d differs:\ngenerated:  %#v\nreflective: %#v", value, value, i, got[i], want[i])
+			}
+		}
+	}
+}
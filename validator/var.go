@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+)
+
+// Names of the values of Var and VarWithField in errors; tags refer to the other value of
+// VarWithField by its name, e.g. VarWithField(confirm, password, "eqfield=Other")
+const (
+	varFieldName   = "Value"
+	otherFieldName = "Other"
+)
+
+// Var validates a single value with the default Validator
+func Var(value interface{}, tag string) ValidationErrors {
+	return defaultValidator.Var(value, tag)
+}
+
+// VarWithField validates a value against another with the default Validator
+func VarWithField(value, other interface{}, tag string) ValidationErrors {
+	return defaultValidator.VarWithField(value, other, tag)
+}
+
+// Var validates a single value against the rules of tag, written as in a struct tag.
+// Errors report the value as "Value", e.g. Var("", "required") fails with "Value is required".
+func (v *Validator) Var(value interface{}, tag string) ValidationErrors {
+	return v.VarContext(context.Background(), value, tag)
+}
+
+// VarContext is like Var, passing ctx to context-aware rules
+func (v *Validator) VarContext(ctx context.Context, value interface{}, tag string) ValidationErrors {
+	return v.validateVars(ctx, tag, value)
+}
+
+// VarWithField is like Var, with cross-field rules of tag comparing value with other,
+// which is referred to as "Other", e.g. VarWithField(end, start, "gtfield=Other")
+func (v *Validator) VarWithField(value, other interface{}, tag string) ValidationErrors {
+	return v.VarWithFieldContext(context.Background(), value, other, tag)
+}
+
+// VarWithFieldContext is like VarWithField, passing ctx to context-aware rules
+func (v *Validator) VarWithFieldContext(ctx context.Context, value, other interface{}, tag string) ValidationErrors {
+	return v.validateVars(ctx, tag, value, other)
+}
+
+// validateVars validates values as the fields Value and Other of a struct, where Value
+// is tagged with tag. reflect.StructOf returns the same type for the same tag,
+// so the compiled plan is cached like the plan of any other struct.
+// Neither the types nor the plans are ever released, which is why tags should come
+// from a fixed set rather than be built from input.
+func (v *Validator) validateVars(ctx context.Context, tag string, values ...interface{}) ValidationErrors {
+	names := []string{varFieldName, otherFieldName}
+	fields := make([]reflect.StructField, len(values))
+	for i, value := range values {
+		typ := reflect.TypeOf(value)
+		if typ == nil {
+			typ = reflect.TypeOf((*interface{})(nil)).Elem()
+		}
+		fields[i] = reflect.StructField{Name: names[i], Type: typ}
+	}
+	fields[0].Tag = reflect.StructTag(v.TagName() + ":" + strconv.Quote(tag))
+
+	holder := reflect.New(reflect.StructOf(fields)).Elem()
+
+	// Tag errors name the tag alone, as CheckTag does, rather than the synthetic struct
+	if errs := v.structPlan(holder.Type()).errs; len(errs) > 0 {
+		err := *errs[0]
+		err.Struct, err.Field = "", ""
+		path := rootPath.field(varFieldName, varFieldName)
+		return ValidationErrors{{Field: path.name, Message: err.Error(), Code: CodeInvalidTag, Namespace: path.name, JSONPath: path.json}}
+	}
+	for i, value := range values {
+		if value != nil {
+			holder.Field(i).Set(reflect.ValueOf(value))
+		}
+	}
+	return v.validateGroups(ctx, holder, nil)
+}